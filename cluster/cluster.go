@@ -2,45 +2,58 @@
 package cluster
 
 import (
+	"context"
 	"errors"
+	"strings"
 
 	"github.com/knightjdr/hclust/typedef"
 )
 
-// Cluster clusters a square symmetric matrix and returns a dendrogram. Linkage
-// method options are: average, centroid, complete, mcquitty, median, single and ward.
-func Cluster(matrix [][]float64, method string) (dendrogram []typedef.SubCluster, err error) {
-	// Return if matrix row and column numbers are not equal. This is to ensure
-	// the matrix is symmetric (likely will be in this case).
-	colDim := len(matrix[0])
-	rowDim := len(matrix)
-	if colDim != rowDim {
-		err = errors.New("The matrix must be symmetric")
-		return
+// updateFunction calculates the row/column to add to a distance matrix when
+// nodes a and b are merged.
+type updateFunction func(matrix [][]float64, a, b int, nodeSize []int) (newRow []float64)
+
+// linkageVariant splits a linkage method into its base method and whether the
+// input distances should be squared before clustering (and the square root taken
+// of the merge heights). Ward, centroid and median accept the suffix ".D" to
+// cluster the distances as supplied or ".D2" to square them, with ".D2" being
+// the default when no suffix is given.
+func linkageVariant(method string) (base string, squared bool, err error) {
+	base = method
+	suffix := ""
+	if index := strings.Index(method, "."); index >= 0 {
+		base = method[:index]
+		suffix = method[index:]
 	}
 
-	// Matrix dimension.
-	N := rowDim
-
-	// Linkage.
-	dendrogram = make([]typedef.SubCluster, N-1)
-	if method == "single" {
-		dendrogram = Single(matrix)
-	} else if method == "average" {
-		dendrogram, err = NearestNeighbor(matrix, method)
-	} else if method == "complete" {
-		dendrogram, err = NearestNeighbor(matrix, method)
-	} else if method == "mcquitty" {
-		dendrogram, err = NearestNeighbor(matrix, method)
-	} else if method == "ward" {
-		dendrogram, err = NearestNeighbor(matrix, method)
-	} else if method == "centroid" {
-		dendrogram, err = Generic(matrix, method)
-	} else if method == "median" {
-		dendrogram, err = Generic(matrix, method)
-	} else {
-		err = errors.New("Unkown linkage method")
+	if base != "centroid" && base != "median" && base != "ward" {
+		if suffix != "" {
+			err = errors.New("Unknown linkage method")
+		}
+		return
 	}
 
+	if suffix == "" || suffix == ".D2" {
+		squared = true
+	} else if suffix != ".D" {
+		err = errors.New("Unknown linkage method")
+	}
 	return
 }
+
+// linkageNodes provides the distances between nodes and merges nodes for
+// algorithms that calculate node distances on demand.
+type linkageNodes interface {
+	distance(a, b int) float64
+	merge(a, b, node int)
+}
+
+// Cluster clusters a square symmetric matrix and returns a dendrogram. Linkage
+// method options are: average, centroid, complete, mcquitty, median, single and ward.
+// Ward, centroid and median square the input distances and report the square root
+// of the merge heights (R's "ward.D2"). Append ".D" to these methods (e.g. "ward.D")
+// to cluster the distances as supplied, such as when they are already squared.
+// The ".D2" suffix can be used to make the default explicit.
+func Cluster(matrix [][]float64, method string) (dendrogram typedef.Dendrogram, err error) {
+	return ClusterContext(context.Background(), matrix, method, nil)
+}
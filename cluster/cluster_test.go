@@ -3,6 +3,7 @@ package cluster
 import (
 	"testing"
 
+	"github.com/knightjdr/hclust/matrixop"
 	"github.com/knightjdr/hclust/typedef"
 	"github.com/stretchr/testify/assert"
 )
@@ -31,7 +32,7 @@ func TestCluster(t *testing.T) {
 	assert.NotNil(t, err, "Unknown linkage method should return error")
 
 	// TEST3: single linkage.
-	want := []typedef.SubCluster{
+	want := typedef.Dendrogram{
 		{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5},
 		{Leafa: 5, Leafb: 1, Lengtha: 1.9, Lengthb: 2.9, Node: 6},
 		{Leafa: 2, Leafb: 3, Lengtha: 6.1, Lengthb: 6.1, Node: 7},
@@ -349,4 +350,63 @@ func TestCluster(t *testing.T) {
 			"Parent node in subcluster not correct for median linkage",
 		)
 	}
+
+	// TEST10: dendrogram for ward.D method.
+	dist = [][]float64{
+		{0, 10, 23, 22.6, 2},
+		{10, 0, 17.8, 17.4, 5.8},
+		{23, 17.8, 0, 12.2, 14.1},
+		{22.6, 17.4, 12.2, 0, 9},
+		{2, 5.8, 14.1, 9, 0},
+	}
+	want = []typedef.SubCluster{
+		{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5},
+		{Leafa: 1, Leafb: 5, Lengtha: 4.93, Lengthb: 3.93, Node: 6},
+		{Leafa: 2, Leafb: 3, Lengtha: 6.1, Lengthb: 6.1, Node: 7},
+		{Leafa: 6, Leafb: 7, Lengtha: 9.81, Lengthb: 8.65, Node: 8},
+	}
+	dendrogram, err = Cluster(dist, "ward.D")
+	assert.Nil(t, err, "Ward.D linkage should not return an error")
+	for i, cluster := range dendrogram {
+		assert.Equal(t, want[i].Leafa, cluster.Leafa, "Leaf a not added to dendrogram correctly for ward.D linkage")
+		assert.Equal(t, want[i].Leafb, cluster.Leafb, "Leaf b not added to dendrogram correctly for ward.D linkage")
+		assert.InDeltaf(t, want[i].Lengtha, cluster.Lengtha, 0.01, "Dendrogram branch lengths not correct for ward.D linkage")
+		assert.InDeltaf(t, want[i].Lengthb, cluster.Lengthb, 0.01, "Dendrogram branch lengths not correct for ward.D linkage")
+		assert.Equal(t, want[i].Node, cluster.Node, "Parent node in subcluster not correct for ward.D linkage")
+	}
+
+	// TEST11: ward.D2 should be the same as ward.
+	want, _ = Cluster(dist, "ward")
+	dendrogram, _ = Cluster(dist, "ward.D2")
+	assert.Equal(t, want, dendrogram, "Ward.D2 linkage should match ward linkage")
+
+	// TEST12: centroid.D on squared distances should give squared heights.
+	dist = [][]float64{
+		{0, 10, 23, 22.6, 2},
+		{10, 0, 17.8, 17.4, 5.8},
+		{23, 17.8, 0, 12.2, 14.1},
+		{22.6, 17.4, 12.2, 0, 15},
+		{2, 5.8, 14.1, 15, 0},
+	}
+	want = []typedef.SubCluster{
+		{Leafa: 0, Leafb: 4, Lengtha: 2, Lengthb: 2, Node: 5},
+		{Leafa: 1, Leafb: 5, Lengtha: 32.91, Lengthb: 30.91, Node: 6},
+		{Leafa: 2, Leafb: 3, Lengtha: 74.42, Lengthb: 74.42, Node: 7},
+		{Leafa: 6, Leafb: 7, Lengtha: 114.44, Lengthb: 72.93, Node: 8},
+	}
+	dendrogram, err = Cluster(matrixop.Square(dist), "centroid.D")
+	assert.Nil(t, err, "Centroid.D linkage should not return an error")
+	for i, cluster := range dendrogram {
+		assert.Equal(t, want[i].Leafa, cluster.Leafa, "Leaf a not added to dendrogram correctly for centroid.D linkage")
+		assert.Equal(t, want[i].Leafb, cluster.Leafb, "Leaf b not added to dendrogram correctly for centroid.D linkage")
+		assert.InDeltaf(t, want[i].Lengtha, cluster.Lengtha, 0.01, "Dendrogram branch lengths not correct for centroid.D linkage")
+		assert.InDeltaf(t, want[i].Lengthb, cluster.Lengthb, 0.01, "Dendrogram branch lengths not correct for centroid.D linkage")
+		assert.Equal(t, want[i].Node, cluster.Node, "Parent node in subcluster not correct for centroid.D linkage")
+	}
+
+	// TEST13: variants are only available for ward, centroid and median.
+	_, err = Cluster(dist, "average.D")
+	assert.NotNil(t, err, "Variant of average linkage should return error")
+	_, err = Cluster(dist, "ward.D3")
+	assert.NotNil(t, err, "Unknown ward variant should return error")
 }
@@ -0,0 +1,67 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// run checks for cancellation and reports progress between merges.
+type run struct {
+	ctx      context.Context
+	progress typedef.Progress
+	total    int
+}
+
+// background returns a run that is never cancelled and does not report progress.
+func background() *run {
+	return &run{ctx: context.Background()}
+}
+
+// merged is called after each merge. It reports progress and returns an error if
+// the run has been cancelled.
+func (r *run) merged(completed int) error {
+	if err := r.ctx.Err(); err != nil {
+		return err
+	}
+	if r.progress != nil {
+		r.progress(completed, r.total)
+	}
+	return nil
+}
+
+// ClusterContext is the same as Cluster but stops with the context's error if it
+// is cancelled. Cancellation is checked between merges. If progress is not nil
+// it is called after each merge with the number of merges completed and the total
+// number of merges.
+func ClusterContext(ctx context.Context, matrix [][]float64, method string, progress typedef.Progress) (dendrogram typedef.Dendrogram, err error) {
+	// Return if matrix row and column numbers are not equal. This is to ensure
+	// the matrix is symmetric (likely will be in this case).
+	colDim := len(matrix[0])
+	rowDim := len(matrix)
+	if colDim != rowDim {
+		err = errors.New("The matrix must be symmetric")
+		return
+	}
+
+	// Split method variant.
+	base, _, err := linkageVariant(method)
+	if err != nil {
+		return
+	}
+
+	// Linkage.
+	r := &run{ctx: ctx, progress: progress, total: rowDim - 1}
+	if base == "single" {
+		dendrogram, err = singleLinkage(r, matrix)
+	} else if base == "average" || base == "complete" || base == "mcquitty" || base == "ward" {
+		dendrogram, err = nearestNeighborLinkage(r, matrix, method)
+	} else if base == "centroid" || base == "median" {
+		dendrogram, err = genericLinkage(r, matrix, method)
+	} else {
+		err = errors.New("Unkown linkage method")
+	}
+
+	return
+}
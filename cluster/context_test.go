@@ -0,0 +1,45 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterContext(t *testing.T) {
+	dist := [][]float64{
+		{0, 10, 23, 22.6, 2},
+		{10, 0, 17.8, 17.4, 5.8},
+		{23, 17.8, 0, 12.2, 14.1},
+		{22.6, 17.4, 12.2, 0, 9},
+		{2, 5.8, 14.1, 9, 0},
+	}
+
+	// TEST1: progress is reported after every merge for each algorithm.
+	for _, method := range []string{"single", "average", "centroid"} {
+		completed := make([]int, 0)
+		progress := func(done, total int) {
+			assert.Equal(t, 4, total, "Total merges not correct for "+method)
+			completed = append(completed, done)
+		}
+		want, _ := Cluster(dist, method)
+		dendrogram, err := ClusterContext(context.Background(), dist, method, progress)
+		assert.Nil(t, err, "Clustering should not return an error for "+method)
+		assert.Equal(t, want, dendrogram, "Dendrogram should match Cluster for "+method)
+		assert.Equal(t, []int{1, 2, 3, 4}, completed, "Progress not reported correctly for "+method)
+	}
+
+	// TEST2: cancelled context stops clustering.
+	for _, method := range []string{"single", "average", "centroid"} {
+		ctx, cancel := context.WithCancel(context.Background())
+		progress := func(done, total int) {
+			if done == 2 {
+				cancel()
+			}
+		}
+		dendrogram, err := ClusterContext(ctx, dist, method, progress)
+		assert.Equal(t, context.Canceled, err, "Cancelled context should return error for "+method)
+		assert.Nil(t, dendrogram, "Cancelled context should not return a dendrogram for "+method)
+	}
+}
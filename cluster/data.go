@@ -0,0 +1,254 @@
+package cluster
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+
+	"github.com/knightjdr/hclust/tree"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// centroidData stores the centroid and size of every node when clustering
+// directly from a data matrix. Centroids are released once a node has been
+// merged so memory stays proportional to the number of active nodes.
+type centroidData struct {
+	centroids [][]float64
+	nodeSize  []int
+	ward      bool
+}
+
+// merge creates the centroid for a new node from nodes a and b.
+func (c *centroidData) merge(a, b, node int) {
+	sizeA := float64(c.nodeSize[a])
+	sizeB := float64(c.nodeSize[b])
+	centroid := make([]float64, len(c.centroids[a]))
+	for i := range centroid {
+		centroid[i] = ((sizeA * c.centroids[a][i]) + (sizeB * c.centroids[b][i])) / (sizeA + sizeB)
+	}
+	c.centroids[node] = centroid
+	c.nodeSize[node] = c.nodeSize[a] + c.nodeSize[b]
+	c.centroids[a] = nil
+	c.centroids[b] = nil
+}
+
+// distance calculates the Ward or centroid distance between nodes a and b.
+func (c *centroidData) distance(a, b int) float64 {
+	if c.ward {
+		return c.wardDistance(a, b)
+	}
+	return c.squaredDistance(a, b)
+}
+
+// squaredDistance calculates the squared Euclidean distance between the
+// centroids of nodes a and b.
+func (c *centroidData) squaredDistance(a, b int) (dist float64) {
+	x := c.centroids[a]
+	y := c.centroids[b]
+	for i := range x {
+		diff := x[i] - y[i]
+		dist += diff * diff
+	}
+	return
+}
+
+// wardDistance calculates the Ward distance between nodes a and b. This is on
+// the same scale as the Lance-Williams update for Ward using squared Euclidean
+// distances.
+func (c *centroidData) wardDistance(a, b int) float64 {
+	sizeA := float64(c.nodeSize[a])
+	sizeB := float64(c.nodeSize[b])
+	return ((2 * sizeA * sizeB) / (sizeA + sizeB)) * c.squaredDistance(a, b)
+}
+
+// candidateQueue is a priority queue of nodes ordered by the distance to
+// their nearest neighbor candidate.
+type candidateQueue struct {
+	dist     []float64
+	nodes    []int
+	position []int
+}
+
+func (q candidateQueue) Len() int { return len(q.nodes) }
+func (q candidateQueue) Less(i, j int) bool {
+	return q.dist[q.nodes[i]] < q.dist[q.nodes[j]]
+}
+func (q candidateQueue) Swap(i, j int) {
+	q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i]
+	q.position[q.nodes[i]] = i
+	q.position[q.nodes[j]] = j
+}
+func (q *candidateQueue) Push(x interface{}) {
+	node := x.(int)
+	q.position[node] = len(q.nodes)
+	q.nodes = append(q.nodes, node)
+}
+func (q *candidateQueue) Pop() interface{} {
+	last := len(q.nodes) - 1
+	node := q.nodes[last]
+	q.nodes = q.nodes[:last]
+	q.position[node] = -1
+	return node
+}
+
+// genericMerge merges n leafs using the generic algorithm of Müllner. It is
+// used for centroid linkage from a data matrix, where node distances are
+// calculated on demand rather than read from a matrix. Each node
+// stores a nearest neighbor candidate with a higher index and a lower bound on
+// the distance to it. Nodes are kept in a priority queue ordered by that bound
+// and candidates are only recalculated when they reach the front of the queue.
+func genericMerge(r *run, nodes linkageNodes, n int) (dendrogram []typedef.SubCluster, err error) {
+	active := make([]bool, 2*n-1)
+	neighbor := make([]int, 2*n-1)
+	queue := &candidateQueue{
+		dist:     make([]float64, 2*n-1),
+		nodes:    make([]int, 0, n),
+		position: make([]int, 2*n-1),
+	}
+
+	// findNeighbor finds the nearest active node with a higher index than a.
+	findNeighbor := func(a, last int) {
+		queue.dist[a] = math.MaxFloat64
+		neighbor[a] = a
+		for i := a + 1; i <= last; i++ {
+			if !active[i] {
+				continue
+			}
+			if dist := nodes.distance(a, i); dist < queue.dist[a] {
+				queue.dist[a] = dist
+				neighbor[a] = i
+			}
+		}
+	}
+
+	// Generate queue with nearest neighbor list.
+	for i := 0; i < n; i++ {
+		active[i] = true
+	}
+	for i := 0; i < n; i++ {
+		findNeighbor(i, n-1)
+		queue.nodes = append(queue.nodes, i)
+		queue.position[i] = i
+	}
+	heap.Init(queue)
+
+	for node := n; node < 2*n-1; node++ {
+		// Get the node with the closest candidate. If the candidate distance is
+		// only a lower bound, find its nearest neighbor and try again.
+		a := queue.nodes[0]
+		b := neighbor[a]
+		dist := nodes.distance(a, b)
+		for dist != queue.dist[a] {
+			findNeighbor(a, node-1)
+			heap.Fix(queue, queue.position[a])
+			a = queue.nodes[0]
+			b = neighbor[a]
+			dist = nodes.distance(a, b)
+		}
+
+		// Add new subcluster to dendrogram.
+		dendrogram = append(
+			dendrogram,
+			typedef.SubCluster{
+				Leafa:   a,
+				Leafb:   b,
+				Lengtha: dist,
+				Lengthb: dist,
+				Node:    node,
+			},
+		)
+
+		// Remove a and b and create the new node.
+		heap.Remove(queue, queue.position[a])
+		heap.Remove(queue, queue.position[b])
+		active[a] = false
+		active[b] = false
+		nodes.merge(a, b, node)
+		active[node] = true
+
+		// Candidates that were a or b now refer to the new node and keep their
+		// distance as a lower bound. Nodes closer to the new node than their
+		// current candidate are updated.
+		for i := 0; i < node; i++ {
+			if !active[i] {
+				continue
+			}
+			if neighbor[i] == a || neighbor[i] == b {
+				neighbor[i] = node
+			}
+			if nodeDist := nodes.distance(i, node); nodeDist < queue.dist[i] {
+				queue.dist[i] = nodeDist
+				neighbor[i] = node
+				heap.Fix(queue, queue.position[i])
+			}
+		}
+
+		// Add the new node to the queue. It has no nodes with a higher index so
+		// its distance is infinite.
+		queue.dist[node] = math.MaxFloat64
+		neighbor[node] = node
+		heap.Push(queue, node)
+
+		if err = r.merged(node - n + 1); err != nil {
+			return nil, err
+		}
+	}
+
+	return
+}
+
+// FromData clusters the row vectors of a data matrix using Euclidean distances
+// without calculating a distance matrix. Distances between nodes are calculated
+// from their centroids and sizes so memory use is O(n·d) rather than O(n²).
+// Linkage method options are: centroid and ward (and their ".D2" forms). The
+// dendrogram matches the one produced by clustering the Euclidean distance
+// matrix with Cluster.
+func FromData(matrix [][]float64, method string) (dendrogram typedef.Dendrogram, err error) {
+	base, squared, err := linkageVariant(method)
+	if err != nil {
+		return
+	}
+	if !squared || (base != "centroid" && base != "ward") {
+		err = errors.New("Linkage method must be one of centroid or ward when clustering from data")
+		return
+	}
+	if len(matrix) < 2 {
+		err = errors.New("The matrix must have at least two rows")
+		return
+	}
+
+	// Number of leafs.
+	n := len(matrix)
+
+	// Initialize centroids with the input rows.
+	data := centroidData{
+		centroids: make([][]float64, 2*n-1),
+		nodeSize:  make([]int, 2*n-1),
+		ward:      base == "ward",
+	}
+	for i, row := range matrix {
+		if len(row) != len(matrix[0]) {
+			err = errors.New("All rows in the matrix must have the same length")
+			return
+		}
+		data.centroids[i] = row
+		data.nodeSize[i] = 1
+	}
+
+	if base == "ward" {
+		dendrogram, _ = chainMerge(background(), &data, n)
+	} else {
+		dendrogram, _ = genericMerge(background(), &data, n)
+	}
+
+	// Take the square root of all lengths.
+	for i := range dendrogram {
+		dendrogram[i].Lengtha = math.Sqrt(dendrogram[i].Lengtha)
+		dendrogram[i].Lengthb = math.Sqrt(dendrogram[i].Lengthb)
+	}
+
+	// Label dendrogram and add branch lengths.
+	dendrogram = tree.AddNodes(dendrogram)
+
+	return
+}
@@ -0,0 +1,45 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/knightjdr/hclust/distance"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromData(t *testing.T) {
+	data := [][]float64{
+		{1, 2.5, 0.3},
+		{4.2, 1, 3},
+		{7.5, 8.1, 2.2},
+		{6.9, 9, 4.1},
+		{1.4, 2, 0.1},
+		{3.3, 0.4, 2.6},
+		{9.1, 7.7, 3.9},
+	}
+	dist := distance.Distance(data, "euclidean", false)
+
+	// TEST1: invalid method should return err.
+	_, err := FromData(data, "average")
+	assert.NotNil(t, err, "Invalid method should return error")
+	_, err = FromData(data, "ward.D")
+	assert.NotNil(t, err, "Ward.D should return error")
+
+	// TEST2: rows of unequal length should return err.
+	_, err = FromData([][]float64{{1, 2}, {3}}, "ward")
+	assert.NotNil(t, err, "Rows of unequal length should return error")
+
+	// TEST3: ward and centroid should match clustering of the distance matrix.
+	for _, method := range []string{"ward", "centroid"} {
+		want, _ := Cluster(dist, method)
+		dendrogram, err := FromData(data, method)
+		assert.Nil(t, err, "Clustering from data should not return an error")
+		for i, cluster := range dendrogram {
+			assert.Equal(t, want[i].Leafa, cluster.Leafa, "Leaf a not added to dendrogram correctly for "+method)
+			assert.Equal(t, want[i].Leafb, cluster.Leafb, "Leaf b not added to dendrogram correctly for "+method)
+			assert.InDelta(t, want[i].Lengtha, cluster.Lengtha, 0.000001, "Dendrogram branch lengths not correct for "+method)
+			assert.InDelta(t, want[i].Lengthb, cluster.Lengthb, 0.000001, "Dendrogram branch lengths not correct for "+method)
+			assert.Equal(t, want[i].Node, cluster.Node, "Parent node in subcluster not correct for "+method)
+		}
+	}
+}
@@ -17,19 +17,60 @@ type neighborInfo struct {
 }
 
 // Generic clusters a distance matrix using a generic algorithm and one of the
-// following linkage methods: centroid or median.
-func Generic(matrix [][]float64, method string) (dendrogram []typedef.SubCluster, err error) {
+// following linkage methods: centroid or median. Methods can be suffixed with
+// ".D" or ".D2" to select whether the input is squared (see Cluster).
+func Generic(matrix [][]float64, method string) (dendrogram typedef.Dendrogram, err error) {
+	return genericLinkage(background(), matrix, method)
+}
+
+// genericLinkage clusters a distance matrix using the generic algorithm,
+// checking for cancellation between merges.
+func genericLinkage(r *run, matrix [][]float64, method string) (dendrogram typedef.Dendrogram, err error) {
+	// Split method variant.
+	base, squared, err := linkageVariant(method)
+	if err != nil {
+		return
+	}
+
 	// Update method.
-	updateFunc, err := UpdateGeneric(method)
+	updateFunc, err := UpdateGeneric(base)
 	if err != nil {
 		return
 	}
 
-	// Number of leafs.
-	n := len(matrix)
+	// Square values in matrix for the ".D2" variants. Otherwise work on a copy
+	// so the input matrix is left unmodified.
+	var dist [][]float64
+	if squared {
+		dist = matrixop.Square(matrix)
+	} else {
+		dist = matrixop.Copy(matrix)
+	}
 
-	// Square values in matrix.
-	dist := matrixop.Square(matrix)
+	dendrogram, err = generic(r, dist, updateFunc)
+	if err != nil {
+		return
+	}
+
+	// Take the square root of all lengths for the ".D2" variants.
+	if squared {
+		for i := range dendrogram {
+			dendrogram[i].Lengtha = math.Sqrt(dendrogram[i].Lengtha)
+			dendrogram[i].Lengthb = math.Sqrt(dendrogram[i].Lengthb)
+		}
+	}
+
+	// Label dendrogram and add branch lengths.
+	dendrogram = tree.AddNodes(dendrogram)
+
+	return
+}
+
+// generic merges the nodes in a distance matrix using the generic algorithm
+// and returns the unlabelled merges. The distance matrix is modified in place.
+func generic(r *run, dist [][]float64, updateFunc updateFunction) (dendrogram []typedef.SubCluster, err error) {
+	// Number of leafs.
+	n := len(dist)
 
 	// Leaf labels.
 	labels := make([]int, n)
@@ -132,11 +173,12 @@ func Generic(matrix [][]float64, method string) (dendrogram []typedef.SubCluster
 			}
 		}
 
-		// If b isn't the previously added node, make the newest node the previous last node's
-		// best match.
-		if b != node-1 {
-			previousIndex := matrixop.SliceIndex(len(queue), func(j int) bool { return queue[j].Index == node-1 })
-			queue[previousIndex] = neighborInfo{dist[node-1][node], node - 1, node}
+		// Make the new node the best match of any node that is closer to it than
+		// to its current candidate.
+		for j := range queue {
+			if dist[node][queue[j].Index] < queue[j].Dist {
+				queue[j] = neighborInfo{dist[node][queue[j].Index], queue[j].Index, node}
+			}
 		}
 
 		// Add the new node to the queue. Reference itself as its best match with
@@ -150,16 +192,11 @@ func Generic(matrix [][]float64, method string) (dendrogram []typedef.SubCluster
 
 		// Increment node.
 		node++
-	}
 
-	// Take the square root of all lengths.
-	for i := range dendrogram {
-		dendrogram[i].Lengtha = math.Sqrt(dendrogram[i].Lengtha)
-		dendrogram[i].Lengthb = math.Sqrt(dendrogram[i].Lengthb)
+		if err = r.merged(node - n); err != nil {
+			return nil, err
+		}
 	}
 
-	// Label dendrogram and add branch lengths.
-	dendrogram = tree.AddNodes(dendrogram)
-
 	return
 }
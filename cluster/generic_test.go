@@ -3,6 +3,7 @@ package cluster
 import (
 	"testing"
 
+	"github.com/knightjdr/hclust/distance"
 	"github.com/knightjdr/hclust/typedef"
 	"github.com/stretchr/testify/assert"
 )
@@ -105,4 +106,22 @@ func TestGeneric(t *testing.T) {
 			"Parent node in subcluster not correct",
 		)
 	}
+
+	// TEST4: a node that becomes closer to a new node than to its current
+	// candidate is merged with it (leaf 6 and node 9).
+	data := [][]float64{
+		{1, 2.5, 0.3},
+		{4.2, 1, 3},
+		{7.5, 8.1, 2.2},
+		{6.9, 9, 4.1},
+		{1.4, 2, 0.1},
+		{3.3, 0.4, 2.6},
+		{9.1, 7.7, 3.9},
+	}
+	dist = distance.Distance(data, "euclidean", false)
+	wantPairs := [][2]int{{0, 4}, {1, 5}, {2, 3}, {6, 9}, {7, 8}, {10, 11}}
+	dendrogram, _ = Generic(dist, "centroid")
+	for i, cluster := range dendrogram {
+		assert.Equal(t, wantPairs[i], [2]int{cluster.Leafa, cluster.Leafb}, "Nodes not merged in order of distance")
+	}
 }
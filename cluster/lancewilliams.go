@@ -0,0 +1,189 @@
+package cluster
+
+import (
+	"errors"
+	"math"
+
+	"github.com/knightjdr/hclust/matrixop"
+	"github.com/knightjdr/hclust/tree"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// Coefficient calculates a Lance-Williams coefficient from the sizes of the
+// merging nodes a and b and the size of a third node k.
+type Coefficient func(sizeA, sizeB, sizeK int) float64
+
+// Coefficients holds the coefficients of the Lance-Williams recurrence used to
+// calculate the distance between a node k and the node formed by merging a and b:
+//
+//	d(k, a∪b) = αa·d(k, a) + αb·d(k, b) + β·d(a, b) + γ·|d(k, a) - d(k, b)|
+//
+// A nil coefficient is treated as zero.
+type Coefficients struct {
+	AlphaA Coefficient
+	AlphaB Coefficient
+	Beta   Coefficient
+	Gamma  Coefficient
+}
+
+// Constant returns a coefficient with a fixed value.
+func Constant(value float64) Coefficient {
+	return func(sizeA, sizeB, sizeK int) float64 {
+		return value
+	}
+}
+
+// Flexible returns the coefficients for the flexible-beta linkage of Lance and
+// Williams, with αa = αb = (1 - β) / 2 and γ = 0.
+func Flexible(beta float64) Coefficients {
+	return Coefficients{
+		AlphaA: Constant((1 - beta) / 2),
+		AlphaB: Constant((1 - beta) / 2),
+		Beta:   Constant(beta),
+		Gamma:  Constant(0),
+	}
+}
+
+// FlexibleBeta is the flexible-beta linkage with the commonly used β = -0.25.
+var FlexibleBeta = Flexible(-0.25)
+
+// WPGMA is the weighted pair group method with arithmetic mean. It is equivalent
+// to the mcquitty linkage method.
+var WPGMA = Coefficients{
+	AlphaA: Constant(0.5),
+	AlphaB: Constant(0.5),
+	Beta:   Constant(0),
+	Gamma:  Constant(0),
+}
+
+// value evaluates a coefficient, treating a nil coefficient as zero.
+func (c Coefficient) value(sizeA, sizeB, sizeK int) float64 {
+	if c == nil {
+		return 0
+	}
+	return c(sizeA, sizeB, sizeK)
+}
+
+// reducibleTolerance allows for rounding error when testing the reducibility
+// conditions.
+const reducibleTolerance = 1e-12
+
+// coefficientSizes returns the cluster sizes used for testing if coefficients
+// are reducible for a dataset with n leafs. All sizes are used for small datasets
+// and a geometric series of sizes for larger ones.
+func coefficientSizes(n int) (sizes []int) {
+	if n <= 32 {
+		for i := 1; i <= n; i++ {
+			sizes = append(sizes, i)
+		}
+		return
+	}
+	for i := 1; i < n; i = int(math.Ceil(float64(i) * 1.5)) {
+		sizes = append(sizes, i)
+	}
+	return append(sizes, n)
+}
+
+// Reducible tests if the coefficients satisfy the sufficient conditions for the
+// reducibility property when clustering n leafs: αa ≥ 0, αb ≥ 0,
+// αa + αb + β ≥ 1 and γ ≥ -min(αa, αb). Coefficients that depend on
+// node sizes are evaluated over a grid of size combinations, which includes
+// every size up to 32 leafs but only a geometric series of sizes above that,
+// so coefficients that fail between the sampled sizes may still be reported as
+// reducible.
+func (c Coefficients) Reducible(n int) bool {
+	sizes := coefficientSizes(n)
+	for _, sizeA := range sizes {
+		for _, sizeB := range sizes {
+			for _, sizeK := range sizes {
+				if sizeA+sizeB+sizeK > n {
+					continue
+				}
+				alphaA := c.AlphaA.value(sizeA, sizeB, sizeK)
+				alphaB := c.AlphaB.value(sizeA, sizeB, sizeK)
+				beta := c.Beta.value(sizeA, sizeB, sizeK)
+				gamma := c.Gamma.value(sizeA, sizeB, sizeK)
+				if alphaA < 0 ||
+					alphaB < 0 ||
+					alphaA+alphaB+beta < 1-reducibleTolerance ||
+					gamma < -math.Min(alphaA, alphaB)-reducibleTolerance {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// UpdateLanceWilliams calculates the row/column to add to a distance matrix for
+// a new node using the Lance-Williams recurrence with the supplied coefficients.
+func UpdateLanceWilliams(coefficients Coefficients) func(matrix [][]float64, a, b int, nodeSize []int) (newRow []float64) {
+	return func(matrix [][]float64, a, b int, nodeSize []int) (newRow []float64) {
+		x := matrix[a]
+		y := matrix[b]
+		dim := len(x)
+		newRow = make([]float64, dim+1)
+		for i := 0; i < dim; i++ {
+			// Keep nodes that have already been merged excluded.
+			if x[i] >= math.MaxFloat64 || y[i] >= math.MaxFloat64 {
+				newRow[i] = math.MaxFloat64
+				continue
+			}
+			newRow[i] = coefficients.AlphaA.value(nodeSize[a], nodeSize[b], nodeSize[i]) * x[i]
+			newRow[i] += coefficients.AlphaB.value(nodeSize[a], nodeSize[b], nodeSize[i]) * y[i]
+			newRow[i] += coefficients.Beta.value(nodeSize[a], nodeSize[b], nodeSize[i]) * x[b]
+			newRow[i] += coefficients.Gamma.value(nodeSize[a], nodeSize[b], nodeSize[i]) * math.Abs(x[i]-y[i])
+		}
+
+		// Set self distance to zero.
+		newRow[dim] = 0
+		return
+	}
+}
+
+// checkReducible wraps an update function to record whether any merge breaks
+// the reducibility property the nearest-neighbor chain relies on: the distance
+// from a node k to the merged node must be at least min(d(k, a), d(k, b)).
+func checkReducible(updateFunc updateFunction, reducible *bool) updateFunction {
+	return func(matrix [][]float64, a, b int, nodeSize []int) (newRow []float64) {
+		newRow = updateFunc(matrix, a, b, nodeSize)
+		for i, distance := range newRow[:len(newRow)-1] {
+			if i == a || i == b || distance >= math.MaxFloat64 {
+				continue
+			}
+			lower := math.Min(matrix[a][i], matrix[b][i])
+			if distance < lower-(reducibleTolerance*(1+math.Abs(lower))) {
+				*reducible = false
+			}
+		}
+		return
+	}
+}
+
+// LanceWilliams clusters a square symmetric matrix using a linkage defined by
+// Lance-Williams coefficients. The nearest-neighbor chain algorithm is used when
+// the coefficients are reducible and the generic algorithm otherwise. As
+// Reducible only samples node sizes for large matrices, every update made by
+// the nearest-neighbor chain is also checked and the matrix is clustered again
+// with the generic algorithm if any merge breaks reducibility.
+func LanceWilliams(matrix [][]float64, coefficients Coefficients) (dendrogram typedef.Dendrogram, err error) {
+	if len(matrix) == 0 || len(matrix[0]) != len(matrix) {
+		err = errors.New("The matrix must be symmetric")
+		return
+	}
+
+	dist := matrixop.Copy(matrix)
+	updateFunc := UpdateLanceWilliams(coefficients)
+	reducible := coefficients.Reducible(len(matrix))
+	if reducible {
+		dendrogram, _ = nearestNeighborChain(background(), dist, checkReducible(updateFunc, &reducible))
+	}
+	if !reducible {
+		dendrogram, _ = generic(background(), matrixop.Copy(matrix), updateFunc)
+	}
+
+	// Label dendrogram and add branch lengths.
+	dendrogram = tree.AddNodes(dendrogram)
+
+	return
+}
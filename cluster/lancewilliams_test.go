@@ -0,0 +1,154 @@
+package cluster
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/knightjdr/hclust/matrixop"
+	"github.com/knightjdr/hclust/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoefficients(t *testing.T) {
+	// TEST1: constant coefficient.
+	assert.Equal(t, 0.3, Constant(0.3)(1, 2, 3), "Constant coefficient should return its value")
+
+	// TEST2: flexible beta coefficients.
+	assert.Equal(t, 0.625, FlexibleBeta.AlphaA(1, 1, 1), "Flexible beta alpha not correct")
+	assert.Equal(t, 0.625, FlexibleBeta.AlphaB(1, 1, 1), "Flexible beta alpha not correct")
+	assert.Equal(t, -0.25, FlexibleBeta.Beta(1, 1, 1), "Flexible beta not correct")
+
+	// TEST3: reducible coefficients.
+	ward := Coefficients{
+		AlphaA: func(a, b, k int) float64 { return float64(a+k) / float64(a+b+k) },
+		AlphaB: func(a, b, k int) float64 { return float64(b+k) / float64(a+b+k) },
+		Beta:   func(a, b, k int) float64 { return -float64(k) / float64(a+b+k) },
+	}
+	assert.True(t, WPGMA.Reducible(50), "WPGMA should be reducible")
+	assert.True(t, FlexibleBeta.Reducible(50), "Flexible beta should be reducible")
+	assert.True(t, ward.Reducible(50), "Ward coefficients should be reducible")
+
+	// TEST4: coefficients that are not reducible.
+	median := Coefficients{
+		AlphaA: Constant(0.5),
+		AlphaB: Constant(0.5),
+		Beta:   Constant(-0.25),
+	}
+	centroid := Coefficients{
+		AlphaA: func(a, b, k int) float64 { return float64(a) / float64(a+b) },
+		AlphaB: func(a, b, k int) float64 { return float64(b) / float64(a+b) },
+		Beta:   func(a, b, k int) float64 { return -float64(a*b) / float64((a+b)*(a+b)) },
+	}
+	assert.False(t, median.Reducible(50), "Median coefficients should not be reducible")
+	assert.False(t, centroid.Reducible(50), "Centroid coefficients should not be reducible")
+}
+
+func TestUpdateLanceWilliams(t *testing.T) {
+	dist := [][]float64{
+		{0, 10, 23, 22.6, 2},
+		{10, 0, 17.8, 17.4, 5.8},
+		{23, 17.8, 0, 4.7, 3},
+		{22.6, 17.4, 4.7, 0, 5.8},
+		{2, 5.8, 3, 5.8, 0},
+	}
+	nodeSize := []int{2, 2, 1, 4, 1}
+
+	// TEST1: WPGMA.
+	want := []float64{5, 5, 20.4, 20, 3.9, 0}
+	updateFunc := UpdateLanceWilliams(WPGMA)
+	assert.Equal(t, want, updateFunc(dist, 0, 1, nodeSize), "Distances not correct for WPGMA linkage")
+
+	// TEST2: flexible beta.
+	want = []float64{3.75, 3.75, 23, 22.5, 2.375, 0}
+	updateFunc = UpdateLanceWilliams(FlexibleBeta)
+	assert.InDeltaSlice(t, want, updateFunc(dist, 0, 1, nodeSize), 0.01, "Distances not correct for flexible beta linkage")
+
+	// TEST3: single linkage via gamma.
+	single := Coefficients{
+		AlphaA: Constant(0.5),
+		AlphaB: Constant(0.5),
+		Gamma:  Constant(-0.5),
+	}
+	want = []float64{0, 0, 17.8, 17.4, 2, 0}
+	updateFunc = UpdateLanceWilliams(single)
+	assert.InDeltaSlice(t, want, updateFunc(dist, 0, 1, nodeSize), 0.01, "Distances not correct for single linkage")
+}
+
+func TestLanceWilliams(t *testing.T) {
+	dist := [][]float64{
+		{0, 10, 23, 22.6, 2},
+		{10, 0, 17.8, 17.4, 5.8},
+		{23, 17.8, 0, 12.2, 14.1},
+		{22.6, 17.4, 12.2, 0, 9},
+		{2, 5.8, 14.1, 9, 0},
+	}
+
+	// TEST1: non-symmetric matrix.
+	_, err := LanceWilliams(dist[:4], WPGMA)
+	assert.NotNil(t, err, "Non-symmetric matrix should return error")
+
+	// TEST2: WPGMA should match mcquitty linkage.
+	want, _ := Cluster(dist, "mcquitty")
+	dendrogram, err := LanceWilliams(dist, WPGMA)
+	assert.Nil(t, err, "WPGMA linkage should not return an error")
+	for i, cluster := range dendrogram {
+		assert.Equal(t, want[i].Leafa, cluster.Leafa, "Leaf a not correct for WPGMA linkage")
+		assert.Equal(t, want[i].Leafb, cluster.Leafb, "Leaf b not correct for WPGMA linkage")
+		assert.InDelta(t, want[i].Lengtha, cluster.Lengtha, 0.01, "Branch lengths not correct for WPGMA linkage")
+		assert.InDelta(t, want[i].Lengthb, cluster.Lengthb, 0.01, "Branch lengths not correct for WPGMA linkage")
+		assert.Equal(t, want[i].Node, cluster.Node, "Parent node not correct for WPGMA linkage")
+	}
+
+	// TEST3: input matrix should not be modified.
+	assert.Equal(t, 10.0, dist[0][1], "Input matrix should not be modified")
+
+	// TEST4: non-reducible coefficients should use the generic algorithm and
+	// match median linkage.
+	median := Coefficients{
+		AlphaA: Constant(0.5),
+		AlphaB: Constant(0.5),
+		Beta:   Constant(-0.25),
+	}
+	want, _ = Cluster(dist, "median")
+	dendrogram, _ = LanceWilliams(matrixop.Square(dist), median)
+	for i, cluster := range dendrogram {
+		assert.Equal(t, want[i].Leafa, cluster.Leafa, "Leaf a not correct for median coefficients")
+		assert.Equal(t, want[i].Leafb, cluster.Leafb, "Leaf b not correct for median coefficients")
+		assert.Equal(t, want[i].Node, cluster.Node, "Parent node not correct for median coefficients")
+	}
+
+	// TEST5: coefficients that break reducibility at a size the sampled check
+	// skips should still match the generic algorithm.
+	sizeFour := Coefficients{
+		AlphaA: Constant(0.5),
+		AlphaB: Constant(0.5),
+		Beta: func(a, b, k int) float64 {
+			if a == 4 || b == 4 || k == 4 {
+				return -0.25
+			}
+			return 0
+		},
+	}
+	rng := rand.New(rand.NewSource(0))
+	points := make([][]float64, 40)
+	for i := range points {
+		points[i] = []float64{rng.Float64(), rng.Float64()}
+	}
+	large := make([][]float64, len(points))
+	for i := range large {
+		large[i] = make([]float64, len(points))
+		for j := range large[i] {
+			large[i][j] = math.Hypot(points[i][0]-points[j][0], points[i][1]-points[j][1])
+		}
+	}
+	assert.True(t, sizeFour.Reducible(len(large)), "Sampled check should miss coefficients that fail only at size 4")
+	unlabelled, _ := generic(background(), matrixop.Copy(large), UpdateLanceWilliams(sizeFour))
+	wantHeights := tree.AddNodes(unlabelled).Heights()
+	sort.Float64s(wantHeights)
+	dendrogram, _ = LanceWilliams(large, sizeFour)
+	heights := dendrogram.Heights()
+	sort.Float64s(heights)
+	assert.InDeltaSlice(t, wantHeights, heights, 1e-9, "Should use the generic algorithm when a merge breaks reducibility")
+}
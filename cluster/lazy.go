@@ -0,0 +1,323 @@
+package cluster
+
+import (
+	"container/list"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/knightjdr/hclust/tree"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// DistanceFunc returns the distance between leafs i and j.
+type DistanceFunc func(i, j int) float64
+
+// pairEntry is a cached value for a pair of nodes.
+type pairEntry struct {
+	key   int64
+	value float64
+}
+
+// pairCache is a bounded, least-recently-used cache of values for node pairs.
+// A nil cache stores nothing.
+type pairCache struct {
+	capacity int
+	entries  map[int64]*list.Element
+	order    *list.List
+}
+
+func newPairCache(capacity int) *pairCache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &pairCache{
+		capacity: capacity,
+		entries:  make(map[int64]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// pairKey creates a key for a pair of nodes that is independent of their order.
+func pairKey(a, b int) int64 {
+	if a > b {
+		a, b = b, a
+	}
+	return int64(a)<<32 | int64(b)
+}
+
+func (c *pairCache) get(a, b int) (value float64, ok bool) {
+	if c == nil {
+		return
+	}
+	element, ok := c.entries[pairKey(a, b)]
+	if !ok {
+		return
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*pairEntry).value, true
+}
+
+// peek gets a value without marking it as recently used.
+func (c *pairCache) peek(a, b int) (value float64, ok bool) {
+	if c == nil {
+		return
+	}
+	element, ok := c.entries[pairKey(a, b)]
+	if !ok {
+		return
+	}
+	return element.Value.(*pairEntry).value, true
+}
+
+func (c *pairCache) set(a, b int, value float64) {
+	if c == nil {
+		return
+	}
+	key := pairKey(a, b)
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*pairEntry).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&pairEntry{key: key, value: value})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*pairEntry).key)
+	}
+}
+
+func (c *pairCache) remove(a, b int) {
+	if c == nil {
+		return
+	}
+	key := pairKey(a, b)
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// lazyNodes calculates distances between nodes from the distances between their
+// leafs. For every pair of nodes an aggregate of the leaf distances is
+// calculated (the sum for average, the maximum for complete and the sum of
+// squares for ward). Aggregates can be combined when nodes merge, so cached
+// values are carried forward to new nodes. Values for merged nodes are left in
+// the cache until they are evicted and are used to calculate the aggregates of
+// the nodes they were merged into.
+type lazyNodes struct {
+	active   []bool
+	cache    *pairCache
+	children [][2]int
+	dist     DistanceFunc
+	method   string
+	n        int
+	nodeSize []int
+	within   []float64
+}
+
+func newLazyNodes(n int, dist DistanceFunc, method string, cacheSize int) *lazyNodes {
+	nodes := &lazyNodes{
+		active:   make([]bool, 2*n-1),
+		cache:    newPairCache(cacheSize),
+		children: make([][2]int, n-1),
+		dist:     dist,
+		method:   method,
+		n:        n,
+		nodeSize: make([]int, 2*n-1),
+		within:   make([]float64, 2*n-1),
+	}
+	for i := 0; i < n; i++ {
+		nodes.active[i] = true
+		nodes.nodeSize[i] = 1
+	}
+	return nodes
+}
+
+// combine combines two aggregates of leaf distances.
+func (l *lazyNodes) combine(x, y float64) float64 {
+	if l.method == "complete" {
+		return math.Max(x, y)
+	}
+	return x + y
+}
+
+// aggregate gets the aggregate of leaf distances between nodes a and b, from
+// the cache if possible.
+func (l *lazyNodes) aggregate(a, b int) float64 {
+	if cached, ok := l.cache.get(a, b); ok {
+		return cached
+	}
+	value := l.splitAggregate(a, b)
+	l.cache.set(a, b, value)
+	return value
+}
+
+// splitAggregate calculates the aggregate of leaf distances between nodes a and
+// b by splitting nodes into their children until a pair is cached or both are
+// leafs. The larger node of a pair is split first. Cached values found while
+// splitting are not marked as used.
+func (l *lazyNodes) splitAggregate(a, b int) (value float64) {
+	stack := [][2]int{{a, b}}
+	for len(stack) > 0 {
+		pair := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		x, y := pair[0], pair[1]
+		if cached, ok := l.cache.peek(x, y); ok {
+			value = l.combine(value, cached)
+			continue
+		}
+		if x < l.n && y < l.n {
+			leafDist := l.dist(x, y)
+			if l.method == "ward" {
+				leafDist *= leafDist
+			}
+			value = l.combine(value, leafDist)
+			continue
+		}
+		if l.nodeSize[x] > l.nodeSize[y] {
+			x, y = y, x
+		}
+		children := l.children[y-l.n]
+		stack = append(stack, [2]int{x, children[0]}, [2]int{x, children[1]})
+	}
+	return
+}
+
+func (l *lazyNodes) distance(a, b int) float64 {
+	value := l.aggregate(a, b)
+	sizeA := float64(l.nodeSize[a])
+	sizeB := float64(l.nodeSize[b])
+	if l.method == "average" {
+		return value / (sizeA * sizeB)
+	} else if l.method == "ward" {
+		// Squared distance between centroids scaled to match the Lance-Williams
+		// update for Ward using squared distances.
+		centroidDist := (value / (sizeA * sizeB)) - (l.within[a] / (sizeA * sizeA)) - (l.within[b] / (sizeB * sizeB))
+		return ((2 * sizeA * sizeB) / (sizeA + sizeB)) * centroidDist
+	}
+	return value
+}
+
+func (l *lazyNodes) merge(a, b, node int) {
+	l.within[node] = l.within[a] + l.within[b] + l.aggregate(a, b)
+	l.nodeSize[node] = l.nodeSize[a] + l.nodeSize[b]
+	l.children[node-l.n] = [2]int{a, b}
+
+	// Carry cached aggregates forward to the new node. Values are read without
+	// marking them as used so the merge does not change which values are evicted.
+	// When only one of a pair is cached it is kept, so calculating the aggregate
+	// for the new node only needs the leaf distances for the other child.
+	l.cache.remove(a, b)
+	l.active[a] = false
+	l.active[b] = false
+	for k := 0; k < node && l.cache != nil; k++ {
+		if !l.active[k] {
+			continue
+		}
+		x, okA := l.cache.peek(k, a)
+		y, okB := l.cache.peek(k, b)
+		if okA && okB {
+			l.cache.remove(k, a)
+			l.cache.remove(k, b)
+			l.cache.set(k, node, l.combine(x, y))
+		}
+	}
+	l.active[node] = true
+}
+
+// singleLazy clusters n leafs using the single linkage MST algorithm, only
+// storing the current minimum distance from each leaf to the tree.
+func singleLazy(n int, dist DistanceFunc) (dendrogram []typedef.SubCluster) {
+	inTree := make([]bool, n)
+	minDist := make([]float64, n)
+
+	// Current node.
+	c := 0
+	inTree[c] = true
+	for i := 1; i < n; i++ {
+		minDist[i] = dist(c, i)
+	}
+
+	for i := 0; i < n-1; i++ {
+		// Nearest node.
+		nearest := -1
+		for j := 0; j < n; j++ {
+			if !inTree[j] && (nearest < 0 || minDist[j] < minDist[nearest]) {
+				nearest = j
+			}
+		}
+		dendrogram = append(
+			dendrogram,
+			typedef.SubCluster{
+				Leafa:   c,
+				Leafb:   nearest,
+				Lengtha: minDist[nearest],
+				Lengthb: minDist[nearest],
+				Node:    0,
+			},
+		)
+
+		// Change current node and update distances to the tree.
+		c = nearest
+		inTree[c] = true
+		for j := 0; j < n; j++ {
+			if !inTree[j] {
+				minDist[j] = math.Min(minDist[j], dist(c, j))
+			}
+		}
+	}
+
+	// Sort dendrogram.
+	sort.SliceStable(dendrogram, func(i, j int) bool {
+		return dendrogram[i].Lengtha < dendrogram[j].Lengtha
+	})
+	return
+}
+
+// Lazy clusters n leafs using a function that calculates the distance between
+// two leafs on demand rather than a distance matrix. Linkage method options are:
+// average, complete, single and ward (Ward assumes Euclidean distances and
+// reports merge heights as for "ward.D2"). Single linkage uses O(n) memory.
+// The other methods calculate node distances from leaf distances and cache
+// up to cacheSize node-pair values; set cacheSize to 0 to disable caching. A
+// cache of n(n-1)/2 values calculates each leaf distance once. Smaller caches
+// recalculate evicted values from the leafs, reusing any cached values for the
+// nodes merged into them.
+func Lazy(n int, dist DistanceFunc, method string, cacheSize int) (dendrogram typedef.Dendrogram, err error) {
+	base, squared, err := linkageVariant(method)
+	if err != nil {
+		return
+	}
+	if base == "ward" && !squared {
+		err = errors.New("Ward linkage with lazy distances must use ward.D2")
+		return
+	}
+	if n < 1 {
+		err = errors.New("At least one leaf is required")
+		return
+	}
+
+	if base == "single" {
+		dendrogram = singleLazy(n, dist)
+	} else if base == "average" || base == "complete" || base == "ward" {
+		dendrogram, _ = chainMerge(background(), newLazyNodes(n, dist, base, cacheSize), n)
+	} else {
+		err = errors.New("Linkage method must be one of average, complete, single or ward for lazy distances")
+		return
+	}
+
+	// Take the square root of all lengths for ward.
+	if base == "ward" {
+		for i := range dendrogram {
+			dendrogram[i].Lengtha = math.Sqrt(dendrogram[i].Lengtha)
+			dendrogram[i].Lengthb = math.Sqrt(dendrogram[i].Lengthb)
+		}
+	}
+
+	// Label dendrogram and add branch lengths.
+	dendrogram = tree.AddNodes(dendrogram)
+
+	return
+}
@@ -0,0 +1,125 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/knightjdr/hclust/distance"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairCache(t *testing.T) {
+	cache := newPairCache(2)
+
+	// TEST1: values are stored independent of pair order.
+	cache.set(1, 2, 0.5)
+	value, ok := cache.get(2, 1)
+	assert.True(t, ok, "Cached value should be found")
+	assert.Equal(t, 0.5, value, "Cached value not correct")
+
+	// TEST2: least recently used value is evicted.
+	cache.set(1, 3, 0.7)
+	cache.get(1, 2)
+	cache.set(2, 3, 0.9)
+	_, ok = cache.get(1, 3)
+	assert.False(t, ok, "Least recently used value should be evicted")
+	_, ok = cache.get(1, 2)
+	assert.True(t, ok, "Recently used value should not be evicted")
+
+	// TEST3: peeking at a value does not mark it as used.
+	cache = newPairCache(2)
+	cache.set(1, 2, 0.5)
+	cache.set(1, 3, 0.7)
+	value, ok = cache.peek(1, 2)
+	assert.True(t, ok, "Peeked value should be found")
+	assert.Equal(t, 0.5, value, "Peeked value not correct")
+	cache.set(2, 3, 0.9)
+	_, ok = cache.peek(1, 2)
+	assert.False(t, ok, "Peeked value should still be least recently used")
+
+	// TEST4: a disabled cache stores nothing.
+	cache = newPairCache(0)
+	cache.set(1, 2, 0.5)
+	_, ok = cache.get(1, 2)
+	assert.False(t, ok, "Disabled cache should not store values")
+}
+
+func TestLazyNodes(t *testing.T) {
+	calls := 0
+	dist := func(i, j int) float64 {
+		calls++
+		return float64(i + j)
+	}
+
+	// TEST1: a value cached for one child of a new node is reused when
+	// calculating the value for the new node.
+	nodes := newLazyNodes(4, dist, "average", 10)
+	assert.Equal(t, float64(2), nodes.aggregate(2, 0), "Aggregate not correct for leafs")
+	nodes.aggregate(0, 1)
+	nodes.merge(0, 1, 4)
+	calls = 0
+	assert.Equal(t, float64(5), nodes.aggregate(2, 4), "Aggregate not correct for node")
+	assert.Equal(t, 1, calls, "Should only calculate the distance to the uncached child")
+
+	// TEST2: values cached for both children are carried forward.
+	nodes = newLazyNodes(4, dist, "complete", 10)
+	nodes.aggregate(2, 0)
+	nodes.aggregate(2, 1)
+	nodes.aggregate(0, 1)
+	nodes.merge(0, 1, 4)
+	calls = 0
+	assert.Equal(t, float64(3), nodes.aggregate(2, 4), "Aggregate not correct for node")
+	assert.Equal(t, 0, calls, "Should not calculate any distances")
+}
+
+func TestLazy(t *testing.T) {
+	data := [][]float64{
+		{1, 2.5, 0.3},
+		{4.2, 1, 3},
+		{7.5, 8.1, 2.2},
+		{6.9, 9, 4.1},
+		{1.4, 2, 0.1},
+		{3.3, 0.4, 2.6},
+		{9.1, 7.7, 3.9},
+	}
+	dist := distance.Distance(data, "euclidean", false)
+	lazyDist := distance.Lazy(data, "euclidean", false)
+
+	// TEST1: unsupported methods should return err.
+	_, err := Lazy(len(data), lazyDist, "median", 0)
+	assert.NotNil(t, err, "Unsupported method should return error")
+	_, err = Lazy(len(data), lazyDist, "ward.D", 0)
+	assert.NotNil(t, err, "Ward.D should return error")
+
+	// TEST2: dendrograms should match clustering of the distance matrix with and
+	// without a cache.
+	for _, method := range []string{"single", "average", "complete", "ward"} {
+		want, _ := Cluster(dist, method)
+		for _, cacheSize := range []int{0, 4, 100} {
+			dendrogram, err := Lazy(len(data), lazyDist, method, cacheSize)
+			assert.Nil(t, err, "Lazy clustering should not return an error")
+			for i, cluster := range dendrogram {
+				assert.Equal(t, want[i].Leafa, cluster.Leafa, "Leaf a not added to dendrogram correctly for "+method)
+				assert.Equal(t, want[i].Leafb, cluster.Leafb, "Leaf b not added to dendrogram correctly for "+method)
+				assert.InDelta(t, want[i].Lengtha, cluster.Lengtha, 0.000001, "Dendrogram branch lengths not correct for "+method)
+				assert.InDelta(t, want[i].Lengthb, cluster.Lengthb, 0.000001, "Dendrogram branch lengths not correct for "+method)
+				assert.Equal(t, want[i].Node, cluster.Node, "Parent node in subcluster not correct for "+method)
+			}
+		}
+	}
+
+	// TEST3: a cache with a value for every pair of leafs calculates each leaf
+	// distance once, and a small cache calculates fewer distances than no cache.
+	n := len(data)
+	for _, method := range []string{"average", "complete", "ward"} {
+		calls := make(map[int]int, 0)
+		for _, cacheSize := range []int{0, n, n * (n - 1) / 2} {
+			countDist := func(i, j int) float64 {
+				calls[cacheSize]++
+				return lazyDist(i, j)
+			}
+			Lazy(n, countDist, method, cacheSize)
+		}
+		assert.Equal(t, n*(n-1)/2, calls[n*(n-1)/2], "Should calculate each leaf distance once for "+method)
+		assert.Less(t, calls[n], calls[0], "Small cache should calculate fewer distances for "+method)
+	}
+}
@@ -9,19 +9,62 @@ import (
 )
 
 // NearestNeighbor clusters a distance matrix using one of the following linkage
-// methods: average, complete, mcquitty or ward.
-func NearestNeighbor(matrix [][]float64, method string) (dendrogram []typedef.SubCluster, err error) {
-	// Number of leafs.
-	n := len(matrix)
+// methods: average, complete, mcquitty or ward. Ward can be suffixed with ".D" or
+// ".D2" to select whether the input is squared (see Cluster).
+func NearestNeighbor(matrix [][]float64, method string) (dendrogram typedef.Dendrogram, err error) {
+	return nearestNeighborLinkage(background(), matrix, method)
+}
+
+// nearestNeighborLinkage clusters a distance matrix using the nearest-neighbor
+// chain algorithm, checking for cancellation between merges.
+func nearestNeighborLinkage(r *run, matrix [][]float64, method string) (dendrogram typedef.Dendrogram, err error) {
+	// Split method variant.
+	base, squared, err := linkageVariant(method)
+	if err != nil {
+		return
+	}
 
-	// Square the matrix for ward.
-	dist := make([][]float64, n)
-	if method == "ward" {
+	// Update method.
+	updateFunc, err := UpdateNN(base)
+	if err != nil {
+		return
+	}
+
+	// Square the matrix for ward.D2. Other methods work on a copy so the input
+	// matrix is left unmodified.
+	var dist [][]float64
+	if squared {
 		dist = matrixop.Square(matrix)
 	} else {
-		dist = matrix
+		dist = matrixop.Copy(matrix)
+	}
+
+	dendrogram, err = nearestNeighborChain(r, dist, updateFunc)
+	if err != nil {
+		return
 	}
 
+	// Take the square root of all lengths for ward.D2.
+	if squared {
+		for i := range dendrogram {
+			dendrogram[i].Lengtha = math.Sqrt(dendrogram[i].Lengtha)
+			dendrogram[i].Lengthb = math.Sqrt(dendrogram[i].Lengthb)
+		}
+	}
+
+	// Label dendrogram and add branch lengths.
+	dendrogram = tree.AddNodes(dendrogram)
+
+	return
+}
+
+// nearestNeighborChain merges the nodes in a distance matrix using the
+// nearest-neighbor chain algorithm and returns the unlabelled merges. The
+// distance matrix is modified in place.
+func nearestNeighborChain(r *run, dist [][]float64, updateFunc updateFunction) (dendrogram []typedef.SubCluster, err error) {
+	// Number of leafs.
+	n := len(dist)
+
 	// Leaf labels.
 	labels := make([]int, n)
 	for i := 0; i < n; i++ {
@@ -34,12 +77,6 @@ func NearestNeighbor(matrix [][]float64, method string) (dendrogram []typedef.Su
 		nodeSize[i] = 1
 	}
 
-	// Update method.
-	updateFunc, err := UpdateNN(method)
-	if err != nil {
-		return
-	}
-
 	// Iterate until there is a single cluster remaining.
 	chain := make([]int, 0)
 	node := n // First node to add.
@@ -96,20 +133,92 @@ func NearestNeighbor(matrix [][]float64, method string) (dendrogram []typedef.Su
 		// Append node.
 		labels = append(labels, node)
 
+		if err = r.merged(node - n + 1); err != nil {
+			return nil, err
+		}
+
 		// Increment node.
 		node++
 	}
 
-	// Take the square root of all lengths for ward.
-	if method == "ward" {
-		for i := range dendrogram {
-			dendrogram[i].Lengtha = math.Sqrt(dendrogram[i].Lengtha)
-			dendrogram[i].Lengthb = math.Sqrt(dendrogram[i].Lengthb)
+	return
+}
+
+// chainMerge merges n leafs using the nearest-neighbor chain algorithm with
+// distances calculated on demand. Unlike nearestNeighborChain, the chain is kept
+// between merges.
+func chainMerge(r *run, nodes linkageNodes, n int) (dendrogram []typedef.SubCluster, err error) {
+	// Active nodes.
+	labels := make([]int, n)
+	for i := 0; i < n; i++ {
+		labels[i] = i
+	}
+
+	// nearest finds the nearest active node to a, preferring node "preference"
+	// when distances are tied.
+	nearest := func(a, preference int) (neighbor int, dist float64) {
+		dist = math.MaxFloat64
+		for _, label := range labels {
+			if label == a {
+				continue
+			}
+			labelDist := nodes.distance(a, label)
+			if labelDist < dist || (labelDist == dist && label == preference) {
+				dist = labelDist
+				neighbor = label
+			}
 		}
+		return
 	}
 
-	// Label dendrogram and add branch lengths.
-	dendrogram = tree.AddNodes(dendrogram)
+	chain := make([]int, 0)
+	for node := n; len(labels) > 1; node++ {
+		if len(chain) == 0 {
+			chain = append(chain, labels[0])
+		}
+
+		// Grow chain until reciprocal nearest neighbors are found.
+		var a, b int
+		var dist float64
+		for {
+			a = chain[len(chain)-1]
+			preference := -1
+			if len(chain) > 1 {
+				preference = chain[len(chain)-2]
+			}
+			b, dist = nearest(a, preference)
+			if b == preference {
+				break
+			}
+			chain = append(chain, b)
+		}
 
+		// Order the pair so the node added to the chain first is on the left.
+		a, b = b, a
+		chain = chain[:len(chain)-2]
+
+		dendrogram = append(
+			dendrogram,
+			typedef.SubCluster{
+				Leafa:   a,
+				Leafb:   b,
+				Lengtha: dist,
+				Lengthb: dist,
+				Node:    node,
+			},
+		)
+
+		// Replace a and b with the new node.
+		aIndex := matrixop.SliceIndex(len(labels), func(i int) bool { return labels[i] == a })
+		labels = append(labels[:aIndex], labels[aIndex+1:]...)
+		bIndex := matrixop.SliceIndex(len(labels), func(i int) bool { return labels[i] == b })
+		labels = append(labels[:bIndex], labels[bIndex+1:]...)
+		labels = append(labels, node)
+		nodes.merge(a, b, node)
+
+		if err = r.merged(node - n + 1); err != nil {
+			return nil, err
+		}
+	}
 	return
 }
@@ -11,7 +11,14 @@ import (
 )
 
 // Single clusters a distance matrix using the single (minimum) linkage method.
-func Single(matrix [][]float64) (dendrogram []typedef.SubCluster) {
+func Single(matrix [][]float64) (dendrogram typedef.Dendrogram) {
+	dendrogram, _ = singleLinkage(background(), matrix)
+	return
+}
+
+// singleLinkage clusters a distance matrix using single linkage, checking for
+// cancellation after each node is added to the tree.
+func singleLinkage(r *run, matrix [][]float64) (dendrogram typedef.Dendrogram, err error) {
 	// Number of leafs.
 	n := len(matrix)
 
@@ -61,6 +68,10 @@ func Single(matrix [][]float64) (dendrogram []typedef.SubCluster) {
 			strLabel := strconv.Itoa(label)
 			distance[strLabel] = math.Min(distanceLast[strLabel], matrix[c][label])
 		}
+
+		if err = r.merged(i + 1); err != nil {
+			return nil, err
+		}
 	}
 
 	// Sort dendrogram.
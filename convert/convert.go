@@ -0,0 +1,3 @@
+// Package convert converts dendrograms to and from the formats used by other
+// clustering software.
+package convert
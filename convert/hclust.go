@@ -0,0 +1,176 @@
+package convert
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/knightjdr/hclust/dendrogram"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// Hclust holds the components of an R hclust object. Merges refer to leafs
+// with negative, one-based indices and to previous merges with positive,
+// one-based indices. Order is the one-based leaf order for plotting.
+type Hclust struct {
+	Merge  [][2]int  `json:"merge"`
+	Height []float64 `json:"height"`
+	Order  []int     `json:"order"`
+	Labels []string  `json:"labels"`
+}
+
+// hclustHeader is the header of the CSV representation of an hclust object.
+var hclustHeader = []string{"merge1", "merge2", "height", "order", "labels"}
+
+// ToHclust converts a dendrogram and leaf names to R hclust components. The
+// names should be in the same order as the rows/columns of the distance matrix.
+func ToHclust(dend typedef.Dendrogram, names []string) (h Hclust, err error) {
+	n := len(dend) + 1
+	if len(names) != n {
+		err = errors.New("The names vector must have the same dimension as the leaf number")
+		return
+	}
+
+	// Convert node numbers to R's convention.
+	rNode := func(node int) int {
+		if node < n {
+			return -(node + 1)
+		}
+		return node - n + 1
+	}
+
+	h.Merge = make([][2]int, len(dend))
+	for i, cluster := range dend {
+		h.Merge[i] = [2]int{rNode(cluster.Leafa), rNode(cluster.Leafb)}
+	}
+	h.Height = dend.Heights()
+	h.Order = dendrogram.LeafOrder(dend)
+	for i := range h.Order {
+		h.Order[i]++
+	}
+	h.Labels = make([]string, n)
+	copy(h.Labels, names)
+	return
+}
+
+// FromHclust converts R hclust components to a dendrogram and leaf names. The
+// leaf order is defined by the merges, so the order component is not used.
+func FromHclust(h Hclust) (dendrogram typedef.Dendrogram, names []string, err error) {
+	n := len(h.Merge) + 1
+	if len(h.Height) != len(h.Merge) {
+		err = errors.New("The hclust object must have a height for each merge")
+		return
+	}
+	if len(h.Labels) != 0 && len(h.Labels) != n {
+		err = errors.New("The hclust object must have a label for each leaf")
+		return
+	}
+
+	merges := make([][2]int, len(h.Merge))
+	for i, merge := range h.Merge {
+		for j, rNode := range merge {
+			if rNode < 0 {
+				merges[i][j] = -rNode - 1
+			} else if rNode > 0 {
+				merges[i][j] = rNode + n - 1
+			} else {
+				err = errors.New("The hclust merges cannot contain zero")
+				return
+			}
+		}
+	}
+	dendrogram, _, err = typedef.FromMerges(merges, h.Height, nil, false)
+	if err != nil {
+		return
+	}
+
+	names = make([]string, n)
+	if len(h.Labels) == 0 {
+		for i := range names {
+			names[i] = strconv.Itoa(i + 1)
+		}
+	} else {
+		copy(names, h.Labels)
+	}
+	return
+}
+
+// WriteHclustCSV writes hclust components as comma-separated values with a
+// header. Each row holds a merge and its height followed by an order element
+// and a label. There is one less merge than leafs so the merge and height
+// columns are empty on the last row.
+func WriteHclustCSV(w io.Writer, h Hclust) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(hclustHeader); err != nil {
+		return err
+	}
+	for i := range h.Order {
+		record := make([]string, 5)
+		if i < len(h.Merge) {
+			record[0] = strconv.Itoa(h.Merge[i][0])
+			record[1] = strconv.Itoa(h.Merge[i][1])
+			record[2] = strconv.FormatFloat(h.Height[i], 'g', -1, 64)
+		}
+		record[3] = strconv.Itoa(h.Order[i])
+		if i < len(h.Labels) {
+			record[4] = h.Labels[i]
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadHclustCSV reads hclust components from comma-separated values written by
+// WriteHclustCSV.
+func ReadHclustCSV(r io.Reader) (h Hclust, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(hclustHeader)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return
+	}
+	if len(records) < 2 {
+		err = errors.New("The hclust CSV must have a header and at least one leaf")
+		return
+	}
+	records = records[1:]
+
+	h.Merge = make([][2]int, len(records)-1)
+	h.Height = make([]float64, len(records)-1)
+	h.Order = make([]int, len(records))
+	h.Labels = make([]string, len(records))
+	for i, record := range records {
+		if i < len(records)-1 {
+			if h.Merge[i][0], err = strconv.Atoi(record[0]); err != nil {
+				return
+			}
+			if h.Merge[i][1], err = strconv.Atoi(record[1]); err != nil {
+				return
+			}
+			if h.Height[i], err = strconv.ParseFloat(record[2], 64); err != nil {
+				return
+			}
+		}
+		if h.Order[i], err = strconv.Atoi(record[3]); err != nil {
+			return
+		}
+		h.Labels[i] = record[4]
+	}
+	return
+}
+
+// WriteHclustJSON writes hclust components as a JSON object.
+func WriteHclustJSON(w io.Writer, h Hclust) error {
+	return json.NewEncoder(w).Encode(h)
+}
+
+// ReadHclustJSON reads hclust components from a JSON object.
+func ReadHclustJSON(r io.Reader) (h Hclust, err error) {
+	err = json.NewDecoder(r).Decode(&h)
+	return
+}
@@ -0,0 +1,95 @@
+package convert
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/knightjdr/hclust/cluster"
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHclust(t *testing.T) {
+	dendrogram := []typedef.SubCluster{
+		{Leafa: 0, Leafb: 3, Lengtha: 0.05, Lengthb: 0.05, Node: 6},
+		{Leafa: 2, Leafb: 5, Lengtha: 0.075, Lengthb: 0.075, Node: 7},
+		{Leafa: 1, Leafb: 6, Lengtha: 0.1, Lengthb: 0.05, Node: 8},
+		{Leafa: 4, Leafb: 8, Lengtha: 0.2, Lengthb: 0.1, Node: 9},
+		{Leafa: 7, Leafb: 9, Lengtha: 0.225, Lengthb: 0.1, Node: 10},
+	}
+	names := []string{"leaf0", "leaf1", "leaf2", "leaf3", "leaf4", "leaf5"}
+
+	// TEST1: names vector of incorrect length.
+	_, err := ToHclust(dendrogram, names[:2])
+	assert.NotNil(t, err, "Incorrect length of names vector should return an error")
+
+	// TEST2: convert to hclust.
+	want := Hclust{
+		Merge:  [][2]int{{-1, -4}, {-3, -6}, {-2, 1}, {-5, 3}, {2, 4}},
+		Height: []float64{0.1, 0.15, 0.2, 0.4, 0.6},
+		Order:  []int{3, 6, 5, 2, 1, 4},
+		Labels: names,
+	}
+	h, err := ToHclust(dendrogram, names)
+	assert.Nil(t, err, "Converting to hclust should not return an error")
+	assert.Equal(t, want.Merge, h.Merge, "Hclust merges not correct")
+	assert.InDeltaSlice(t, want.Height, h.Height, 0.000001, "Hclust heights not correct")
+	assert.Equal(t, want.Order, h.Order, "Hclust order not correct")
+	assert.Equal(t, want.Labels, h.Labels, "Hclust labels not correct")
+
+	// TEST3: convert from hclust.
+	actual, actualNames, err := FromHclust(h)
+	assert.Nil(t, err, "Converting from hclust should not return an error")
+	assertDendrogramsEqual(t, dendrogram, actual, "Dendrogram from hclust not correct")
+	assert.Equal(t, names, actualNames, "Names from hclust not correct")
+
+	// TEST4: invalid hclust objects.
+	_, _, err = FromHclust(Hclust{Merge: [][2]int{{-1, 0}}, Height: []float64{1}})
+	assert.NotNil(t, err, "Merge containing zero should return an error")
+	_, _, err = FromHclust(Hclust{Merge: [][2]int{{-1, -2}}, Height: []float64{}})
+	assert.NotNil(t, err, "Missing heights should return an error")
+
+	// TEST5: CSV round trip.
+	var buffer bytes.Buffer
+	err = WriteHclustCSV(&buffer, h)
+	assert.Nil(t, err, "Writing CSV should not return an error")
+	wantCSV := "merge1,merge2,height,order,labels\n" +
+		"-1,-4,0.1,3,leaf0\n" +
+		"-3,-6,0.15,6,leaf1\n" +
+		"-2,1,0.2,5,leaf2\n" +
+		"-5,3,0.4,2,leaf3\n" +
+		"2,4,0.6,1,leaf4\n" +
+		",,,4,leaf5\n"
+	assert.Equal(t, wantCSV, buffer.String(), "Hclust CSV not correct")
+	csvH, err := ReadHclustCSV(&buffer)
+	assert.Nil(t, err, "Reading CSV should not return an error")
+	assert.Equal(t, h, csvH, "Hclust should round trip through CSV")
+
+	// TEST6: JSON round trip.
+	buffer.Reset()
+	err = WriteHclustJSON(&buffer, h)
+	assert.Nil(t, err, "Writing JSON should not return an error")
+	jsonH, err := ReadHclustJSON(&buffer)
+	assert.Nil(t, err, "Reading JSON should not return an error")
+	assert.Equal(t, h, jsonH, "Hclust should round trip through JSON")
+
+	// TEST7: round trip of a clustered dendrogram preserves topology and heights.
+	dist := [][]float64{
+		{0, 10, 23, 22.6, 2},
+		{10, 0, 17.8, 17.4, 5.8},
+		{23, 17.8, 0, 12.2, 14.1},
+		{22.6, 17.4, 12.2, 0, 9},
+		{2, 5.8, 14.1, 9, 0},
+	}
+	for _, method := range []string{"average", "complete", "median"} {
+		clustered, _ := cluster.Cluster(dist, method)
+		h, _ = ToHclust(clustered, names[:5])
+		buffer.Reset()
+		WriteHclustJSON(&buffer, h)
+		h, _ = ReadHclustJSON(&buffer)
+		actual, actualNames, err = FromHclust(h)
+		assert.Nil(t, err, "Round trip should not return an error for "+method)
+		assertDendrogramsEqual(t, clustered, actual, "Round trip not correct for "+method)
+		assert.Equal(t, names[:5], actualNames, "Names should round trip for "+method)
+	}
+}
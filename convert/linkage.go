@@ -0,0 +1,106 @@
+package convert
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// Linkage is a scipy linkage matrix (Z). Each row describes a merge with the
+// indices of the two merged nodes, the merge height and the number of leafs
+// under the new node. Leafs are numbered 0 to n-1 and the node created at row i
+// is numbered n+i.
+type Linkage [][4]float64
+
+// ToLinkage converts a dendrogram to a scipy linkage matrix.
+func ToLinkage(dendrogram typedef.Dendrogram) (z Linkage) {
+	heights := dendrogram.Heights()
+	sizes := dendrogram.Sizes()
+	z = make(Linkage, len(dendrogram))
+	for i, cluster := range dendrogram {
+		z[i] = [4]float64{
+			float64(cluster.Leafa),
+			float64(cluster.Leafb),
+			heights[i],
+			float64(sizes[i]),
+		}
+	}
+	return
+}
+
+// FromLinkage converts a scipy linkage matrix to a dendrogram. The number of
+// leafs in each row must match the merges.
+func FromLinkage(z Linkage) (dendrogram typedef.Dendrogram, err error) {
+	merges := make([][2]int, len(z))
+	heights := make([]float64, len(z))
+	for i, row := range z {
+		if row[0] != math.Trunc(row[0]) || row[1] != math.Trunc(row[1]) {
+			return nil, errors.New("Linkage node indices must be integers")
+		}
+		merges[i] = [2]int{int(row[0]), int(row[1])}
+		heights[i] = row[2]
+	}
+	if dendrogram, _, err = typedef.FromMerges(merges, heights, nil, false); err != nil {
+		return nil, err
+	}
+	for i, row := range z {
+		if row[3] != float64(dendrogram[i].Size) {
+			return nil, errors.New("Linkage leaf counts do not match the merges")
+		}
+	}
+	return
+}
+
+// WriteLinkageCSV writes a linkage matrix as comma-separated values without a
+// header, as read by numpy.loadtxt(file, delimiter=",").
+func WriteLinkageCSV(w io.Writer, z Linkage) error {
+	writer := csv.NewWriter(w)
+	for _, row := range z {
+		record := []string{
+			strconv.Itoa(int(row[0])),
+			strconv.Itoa(int(row[1])),
+			strconv.FormatFloat(row[2], 'g', -1, 64),
+			strconv.Itoa(int(row[3])),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadLinkageCSV reads a linkage matrix from comma-separated values.
+func ReadLinkageCSV(r io.Reader) (z Linkage, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	records, err := reader.ReadAll()
+	if err != nil {
+		return
+	}
+	z = make(Linkage, len(records))
+	for i, record := range records {
+		for j, field := range record {
+			if z[i][j], err = strconv.ParseFloat(field, 64); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return
+}
+
+// WriteLinkageJSON writes a linkage matrix as a JSON array of rows.
+func WriteLinkageJSON(w io.Writer, z Linkage) error {
+	return json.NewEncoder(w).Encode(z)
+}
+
+// ReadLinkageJSON reads a linkage matrix from a JSON array of rows.
+func ReadLinkageJSON(r io.Reader) (z Linkage, err error) {
+	err = json.NewDecoder(r).Decode(&z)
+	return
+}
@@ -0,0 +1,94 @@
+package convert
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/knightjdr/hclust/cluster"
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertDendrogramsEqual(t *testing.T, want, actual []typedef.SubCluster, message string) {
+	assert.Equal(t, len(want), len(actual), message)
+	for i := range want {
+		assert.Equal(t, want[i].Leafa, actual[i].Leafa, message)
+		assert.Equal(t, want[i].Leafb, actual[i].Leafb, message)
+		assert.InDelta(t, want[i].Lengtha, actual[i].Lengtha, 0.000001, message)
+		assert.InDelta(t, want[i].Lengthb, actual[i].Lengthb, 0.000001, message)
+		assert.Equal(t, want[i].Node, actual[i].Node, message)
+	}
+}
+
+func TestLinkage(t *testing.T) {
+	dendrogram := []typedef.SubCluster{
+		{Leafa: 0, Leafb: 3, Lengtha: 0.05, Lengthb: 0.05, Node: 6},
+		{Leafa: 2, Leafb: 5, Lengtha: 0.075, Lengthb: 0.075, Node: 7},
+		{Leafa: 1, Leafb: 6, Lengtha: 0.1, Lengthb: 0.05, Node: 8},
+		{Leafa: 4, Leafb: 8, Lengtha: 0.2, Lengthb: 0.1, Node: 9},
+		{Leafa: 7, Leafb: 9, Lengtha: 0.225, Lengthb: 0.1, Node: 10},
+	}
+
+	// TEST1: convert to linkage matrix.
+	want := Linkage{
+		{0, 3, 0.1, 2},
+		{2, 5, 0.15, 2},
+		{1, 6, 0.2, 3},
+		{4, 8, 0.4, 4},
+		{7, 9, 0.6, 6},
+	}
+	z := ToLinkage(dendrogram)
+	for i := range want {
+		assert.InDeltaSlice(t, want[i][:], z[i][:], 0.000001, "Linkage matrix not correct")
+	}
+
+	// TEST2: convert from linkage matrix.
+	actual, err := FromLinkage(z)
+	assert.Nil(t, err, "Converting a valid linkage matrix should not return an error")
+	assertDendrogramsEqual(t, dendrogram, actual, "Dendrogram from linkage matrix not correct")
+
+	// TEST3: invalid linkage matrices.
+	_, err = FromLinkage(Linkage{{0, 3, 0.1, 2}, {0, 2, 0.2, 3}})
+	assert.NotNil(t, err, "Merging a leaf twice should return an error")
+	_, err = FromLinkage(Linkage{{0, 3, 0.1, 2}, {1, 5, 0.2, 3}})
+	assert.NotNil(t, err, "Merging a node before it exists should return an error")
+	_, err = FromLinkage(Linkage{{0, 1.5, 0.1, 2}})
+	assert.NotNil(t, err, "Non-integer indices should return an error")
+	_, err = FromLinkage(Linkage{{0, 3, 0.1, 2}, {1, 4, 0.2, 2}, {2, 5, 0.3, 4}})
+	assert.NotNil(t, err, "A leaf count that does not match the merges should return an error")
+
+	// TEST4: CSV round trip.
+	var buffer bytes.Buffer
+	err = WriteLinkageCSV(&buffer, z)
+	assert.Nil(t, err, "Writing CSV should not return an error")
+	assert.Equal(t, "0,3,0.1,2\n2,5,0.15,2\n1,6,0.2,3\n4,8,0.4,4\n7,9,0.6,6\n", buffer.String(), "Linkage CSV not correct")
+	csvZ, err := ReadLinkageCSV(&buffer)
+	assert.Nil(t, err, "Reading CSV should not return an error")
+	assert.Equal(t, z, csvZ, "Linkage matrix should round trip through CSV")
+
+	// TEST5: JSON round trip.
+	buffer.Reset()
+	err = WriteLinkageJSON(&buffer, z)
+	assert.Nil(t, err, "Writing JSON should not return an error")
+	jsonZ, err := ReadLinkageJSON(&buffer)
+	assert.Nil(t, err, "Reading JSON should not return an error")
+	assert.Equal(t, z, jsonZ, "Linkage matrix should round trip through JSON")
+
+	// TEST6: round trip of a clustered dendrogram preserves topology and heights.
+	dist := [][]float64{
+		{0, 10, 23, 22.6, 2},
+		{10, 0, 17.8, 17.4, 5.8},
+		{23, 17.8, 0, 12.2, 14.1},
+		{22.6, 17.4, 12.2, 0, 9},
+		{2, 5.8, 14.1, 9, 0},
+	}
+	for _, method := range []string{"average", "centroid", "single", "ward"} {
+		clustered, _ := cluster.Cluster(dist, method)
+		buffer.Reset()
+		WriteLinkageCSV(&buffer, ToLinkage(clustered))
+		z, _ = ReadLinkageCSV(&buffer)
+		actual, err = FromLinkage(z)
+		assert.Nil(t, err, "Round trip should not return an error for "+method)
+		assertDendrogramsEqual(t, clustered, actual, "Round trip not correct for "+method)
+	}
+}
@@ -0,0 +1,120 @@
+// Package cut has methods for cutting a dendrogram into flat clusters.
+package cut
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/knightjdr/hclust/dendrogram"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// K cuts a dendrogram into k clusters and returns the cluster label for each
+// leaf. Clusters are numbered from 1 in the order their first leaf appears
+// in the dendrogram, from left to right.
+func K(dendrogram typedef.Dendrogram, k int) (labels []int, err error) {
+	n := dendrogram.NumLeafs()
+	if k < 1 || k > n {
+		err = errors.New("Number of clusters must be between 1 and the number of leafs")
+		return
+	}
+
+	order := MergeOrder(dendrogram)
+	labels = cutMerges(dendrogram, order[:n-k])
+	return
+}
+
+// Ks cuts a dendrogram into each number of clusters in ks and returns the
+// cluster labels for each cut.
+func Ks(dendrogram typedef.Dendrogram, ks []int) (labels [][]int, err error) {
+	n := dendrogram.NumLeafs()
+	for _, k := range ks {
+		if k < 1 || k > n {
+			err = errors.New("Number of clusters must be between 1 and the number of leafs")
+			return
+		}
+	}
+
+	order := MergeOrder(dendrogram)
+	labels = make([][]int, len(ks))
+	for i, k := range ks {
+		labels[i] = cutMerges(dendrogram, order[:n-k])
+	}
+	return
+}
+
+// Height cuts a dendrogram at a height and returns the cluster label for each
+// leaf. Nodes merged at or below the height are kept together.
+func Height(dendrogram typedef.Dendrogram, height float64) (labels []int) {
+	heights := monotoneHeights(dendrogram)
+	order := MergeOrder(dendrogram)
+	merges := 0
+	for merges < len(order) && heights[order[merges]] <= height {
+		merges++
+	}
+	return cutMerges(dendrogram, order[:merges])
+}
+
+// monotoneHeights gets the merge height of each node, raising any node that is
+// lower than one of its children (as can happen with centroid and median
+// linkage) to the height of that child.
+func monotoneHeights(dendrogram typedef.Dendrogram) []float64 {
+	n := dendrogram.NumLeafs()
+	heights := dendrogram.Heights()
+	for i, cluster := range dendrogram {
+		for _, child := range []int{cluster.Leafa, cluster.Leafb} {
+			if child >= n && heights[child-n] > heights[i] {
+				heights[i] = heights[child-n]
+			}
+		}
+	}
+	return heights
+}
+
+// MergeOrder gets the dendrogram indices sorted by merge height, the order in
+// which K, Ks and Height join clusters. Ties keep their order in the
+// dendrogram, so children are always merged before their parents.
+func MergeOrder(dendrogram typedef.Dendrogram) []int {
+	heights := monotoneHeights(dendrogram)
+	order := make([]int, len(dendrogram))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return heights[order[i]] < heights[order[j]]
+	})
+	return order
+}
+
+// cutMerges joins the leafs under the listed nodes and labels the resulting
+// clusters by leaf order.
+func cutMerges(dend typedef.Dendrogram, merges []int) (labels []int) {
+	n := dend.NumLeafs()
+	cluster := make([]int, 2*n-1)
+	for i := range cluster {
+		cluster[i] = i
+	}
+	find := func(node int) int {
+		for cluster[node] != node {
+			cluster[node] = cluster[cluster[node]]
+			node = cluster[node]
+		}
+		return node
+	}
+	for _, index := range merges {
+		node := dend[index]
+		cluster[find(node.Leafa)] = node.Node
+		cluster[find(node.Leafb)] = node.Node
+	}
+
+	labels = make([]int, n)
+	clusterLabel := make(map[int]int, 0)
+	for _, leaf := range dendrogram.LeafOrder(dend) {
+		root := find(leaf)
+		if _, ok := clusterLabel[root]; !ok {
+			clusterLabel[root] = len(clusterLabel) + 1
+		}
+		labels[leaf] = clusterLabel[root]
+	}
+	return labels
+}
@@ -0,0 +1,86 @@
+package cut
+
+import (
+	"testing"
+
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+var testDendrogram = typedef.Dendrogram{
+	{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5, Height: 2, Size: 2},
+	{Leafa: 5, Leafb: 1, Lengtha: 2.95, Lengthb: 3.95, Node: 6, Height: 7.9, Size: 3},
+	{Leafa: 3, Leafb: 2, Lengtha: 6.1, Lengthb: 6.1, Node: 7, Height: 12.2, Size: 2},
+	{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.56, Node: 8, Height: 17.32, Size: 5},
+}
+
+func TestK(t *testing.T) {
+	// TEST1: cut into a varying number of clusters.
+	labels, err := K(testDendrogram, 1)
+	assert.Nil(t, err, "Should not return an error for valid k")
+	assert.Equal(t, []int{1, 1, 1, 1, 1}, labels, "Should return a single cluster")
+	labels, _ = K(testDendrogram, 2)
+	assert.Equal(t, []int{1, 1, 2, 2, 1}, labels, "Should cut into two clusters")
+	labels, _ = K(testDendrogram, 3)
+	assert.Equal(t, []int{1, 1, 3, 2, 1}, labels, "Should cut into three clusters numbered by leaf order")
+	labels, _ = K(testDendrogram, 5)
+	assert.Equal(t, []int{1, 3, 5, 4, 2}, labels, "Should place each leaf in its own cluster")
+
+	// TEST2: labels follow the leaf order of an optimized dendrogram.
+	optimized := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5, Height: 2, Size: 2},
+		{Leafa: 5, Leafb: 1, Lengtha: 2.95, Lengthb: 3.95, Node: 6, Height: 7.9, Size: 3},
+		{Leafa: 2, Leafb: 3, Lengtha: 6.1, Lengthb: 6.1, Node: 7, Height: 12.2, Size: 2},
+		{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.56, Node: 8, Height: 17.32, Size: 5},
+	}
+	labels, _ = K(optimized, 3)
+	assert.Equal(t, []int{1, 1, 2, 3, 1}, labels, "Should number clusters by optimized leaf order")
+
+	// TEST3: invalid k.
+	_, err = K(testDendrogram, 0)
+	assert.NotNil(t, err, "Should return an error when k is less than one")
+	_, err = K(testDendrogram, 6)
+	assert.NotNil(t, err, "Should return an error when k is greater than the number of leafs")
+}
+
+func TestKs(t *testing.T) {
+	// TEST1: cut into several numbers of clusters.
+	labels, err := Ks(testDendrogram, []int{2, 3})
+	want := [][]int{
+		{1, 1, 2, 2, 1},
+		{1, 1, 3, 2, 1},
+	}
+	assert.Nil(t, err, "Should not return an error for valid ks")
+	assert.Equal(t, want, labels, "Should cut into each number of clusters")
+
+	// TEST2: invalid k.
+	_, err = Ks(testDendrogram, []int{2, 7})
+	assert.NotNil(t, err, "Should return an error when any k is invalid")
+}
+
+func TestHeight(t *testing.T) {
+	// TEST1: cut at varying heights.
+	assert.Equal(t, []int{1, 3, 5, 4, 2}, Height(testDendrogram, 1), "Should place each leaf in its own cluster")
+	assert.Equal(t, []int{1, 2, 4, 3, 1}, Height(testDendrogram, 2), "Should join leafs merged at the cut height")
+	assert.Equal(t, []int{1, 1, 3, 2, 1}, Height(testDendrogram, 8), "Should cut into three clusters")
+	assert.Equal(t, []int{1, 1, 1, 1, 1}, Height(testDendrogram, 20), "Should return a single cluster")
+
+	// TEST2: heights derived from branch lengths.
+	noHeights := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5},
+		{Leafa: 5, Leafb: 1, Lengtha: 2.95, Lengthb: 3.95, Node: 6},
+		{Leafa: 3, Leafb: 2, Lengtha: 6.1, Lengthb: 6.1, Node: 7},
+		{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.56, Node: 8},
+	}
+	assert.Equal(t, []int{1, 1, 3, 2, 1}, Height(noHeights, 8), "Should cut dendrogram without stored heights")
+
+	// TEST3: a node merged below its child (inversion) is cut with its child.
+	inverted := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 1, Node: 3, Height: 2, Size: 2},
+		{Leafa: 3, Leafb: 2, Node: 4, Height: 1.5, Size: 3},
+	}
+	assert.Equal(t, []int{1, 2, 3}, Height(inverted, 1.8), "Should not join inverted node below its child")
+	assert.Equal(t, []int{1, 1, 1}, Height(inverted, 2), "Should join inverted node with its child")
+	labels, _ := K(inverted, 2)
+	assert.Equal(t, []int{1, 1, 2}, labels, "Should cut inverted dendrogram into two clusters")
+}
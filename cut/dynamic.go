@@ -0,0 +1,622 @@
+package cut
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/knightjdr/hclust/dendrogram"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// DynamicOptions are the options for a dynamic tree cut. Numeric options left
+// at zero are set automatically as in R's cutreeDynamic. Options marked as
+// relative are fractions of the range between the reference height (the 5th
+// percentile of merge heights) and the cut height.
+type DynamicOptions struct {
+	// CutHeight is the maximum merge height considered (maxTreeHeight in tree
+	// mode). Leafs merged above it are not assigned to a module. It defaults
+	// to 99% of the range between the reference height and the largest merge
+	// height in hybrid mode and to 0.99 in tree mode.
+	CutHeight float64
+
+	// DeepSplit controls how finely branches are split, from 0 (coarse) to 4
+	// (fine). Tree mode splits deeply for any value above 0.
+	DeepSplit int
+
+	// MaxAbsCoreScatter is the maximum average distance between the core leafs
+	// of a module. It defaults to a height set by MaxCoreScatter.
+	MaxAbsCoreScatter float64
+
+	// MaxCoreScatter is MaxAbsCoreScatter relative to the reference height. It
+	// defaults to a value set by DeepSplit.
+	MaxCoreScatter float64
+
+	// MaxPAMDist is the largest average distance at which the PAM stage
+	// assigns a leaf to a module that it is not already close to. It defaults
+	// to the cut height.
+	MaxPAMDist float64
+
+	// MinAbsGap is the smallest gap between the core scatter of a module and
+	// the height at which it merges with another branch. It defaults to a
+	// height set by MinGap.
+	MinAbsGap float64
+
+	// MinAbsSplitHeight is the height below which branches are always merged.
+	// It defaults to a height set by MinSplitHeight.
+	MinAbsSplitHeight float64
+
+	// MinGap is MinAbsGap relative to the range above the reference height. It
+	// defaults to a value set by DeepSplit.
+	MinGap float64
+
+	// MinModuleSize is the minimum number of leafs in a module.
+	MinModuleSize int
+
+	// MinSplitHeight is MinAbsSplitHeight relative to the reference height.
+	MinSplitHeight float64
+
+	// PAMStage assigns leafs left unassigned by the hybrid cut to the module
+	// with the smallest average distance.
+	PAMStage bool
+
+	// PAMRespectsDendro only assigns leafs in the PAM stage to modules in the
+	// same branch of the dendrogram at the cut height.
+	PAMRespectsDendro bool
+
+	// RespectSmallClusters assigns the leafs of branches that were not modules
+	// only because they were too small to the same module in the PAM stage.
+	RespectSmallClusters bool
+}
+
+// DefaultDynamicOptions returns the default options for a dynamic tree cut.
+func DefaultDynamicOptions() DynamicOptions {
+	return DynamicOptions{
+		DeepSplit:            1,
+		MinModuleSize:        20,
+		PAMStage:             true,
+		PAMRespectsDendro:    true,
+		RespectSmallClusters: true,
+	}
+}
+
+// Maximum core scatter, relative to the range of merge heights, for each
+// level of deepSplit.
+var defaultMaxCoreScatter = []float64{0.64, 0.73, 0.82, 0.91, 0.95}
+
+// Dynamic cuts a dendrogram into modules using the dynamic tree cut of
+// Langfelder et al. (Bioinformatics, 2008) and returns the module label for
+// each leaf. Modules are numbered from 1 in order of decreasing size and
+// unassigned leafs have a label of 0. Valid methods are "hybrid", which uses
+// the dendrogram and the distance matrix, and "tree", which only uses the
+// dendrogram (dist may be nil).
+//
+// The hybrid method follows cutreeHybrid from the R dynamicTreeCut package,
+// with the PAM stage using average distances (useMedoids = FALSE). External
+// split criteria are not available. The tree method follows the description
+// of cutreeDynamicTree: the dendrogram is cut at the cut height and each
+// branch is split recursively at merges above the mean height of its leafs
+// (halfway between the mean and the maximum when not splitting deeply), with
+// runs of leafs too small to be modules joined to the neighbour they are
+// merged with first.
+func Dynamic(dendrogram typedef.Dendrogram, dist [][]float64, method string, options DynamicOptions) (labels []int, err error) {
+	if options.DeepSplit < 0 || options.DeepSplit > 4 {
+		err = errors.New("DeepSplit must be between 0 and 4")
+		return
+	}
+	if options.MinModuleSize < 1 {
+		err = errors.New("The minimum module size must be at least 1")
+		return
+	}
+
+	if method == "hybrid" {
+		n := dendrogram.NumLeafs()
+		if len(dist) != n {
+			err = errors.New("The distance matrix must have one row for each leaf")
+			return
+		}
+		for _, row := range dist {
+			if len(row) != n {
+				err = errors.New("The distance matrix must be square")
+				return
+			}
+		}
+		labels = dynamicHybrid(dendrogram, dist, options)
+	} else if method == "tree" {
+		labels = dynamicTree(dendrogram, options)
+	} else {
+		err = errors.New("Unknown dynamic cut method")
+	}
+	return
+}
+
+// hybridBranch is a branch found while merging nodes in the hybrid cut. Basic
+// branches are candidate modules and hold their leafs in the order they were
+// merged. Composite branches hold the basic branches that were too distinct to
+// merge. A basic branch is top basic until it is merged into another branch.
+type hybridBranch struct {
+	basic    bool
+	basics   []int
+	failSize bool
+	leafs    []int
+	size     int
+	topBasic bool
+}
+
+func dynamicHybrid(dendrogram typedef.Dendrogram, dist [][]float64, options DynamicOptions) []int {
+	n := dendrogram.NumLeafs()
+	if n < 2 {
+		return make([]int, n)
+	}
+	heights := monotoneHeights(dendrogram)
+	order := MergeOrder(dendrogram)
+	maxHeight := heights[order[len(order)-1]]
+	minModuleSize := options.MinModuleSize
+
+	// Scale thresholds to the range between a reference height near the
+	// bottom of the dendrogram and the cut height.
+	refMerge := int(math.RoundToEven(float64(len(order)) * 0.05))
+	if refMerge < 1 {
+		refMerge = 1
+	}
+	refHeight := heights[order[refMerge-1]]
+	cutHeight := options.CutHeight
+	if cutHeight <= 0 {
+		cutHeight = (0.99 * (maxHeight - refHeight)) + refHeight
+	} else if cutHeight > maxHeight {
+		cutHeight = maxHeight
+	}
+	maxCoreScatter := options.MaxCoreScatter
+	if maxCoreScatter == 0 {
+		maxCoreScatter = defaultMaxCoreScatter[options.DeepSplit]
+	}
+	minGap := options.MinGap
+	if minGap == 0 {
+		minGap = (1 - defaultMaxCoreScatter[options.DeepSplit]) * 3 / 4
+	}
+	maxAbsCoreScatter := options.MaxAbsCoreScatter
+	if maxAbsCoreScatter == 0 {
+		maxAbsCoreScatter = refHeight + (maxCoreScatter * (cutHeight - refHeight))
+	}
+	minAbsGap := options.MinAbsGap
+	if minAbsGap == 0 {
+		minAbsGap = minGap * (cutHeight - refHeight)
+	}
+	minAbsSplitHeight := options.MinAbsSplitHeight
+	if minAbsSplitHeight == 0 {
+		minAbsSplitHeight = refHeight + (options.MinSplitHeight * (cutHeight - refHeight))
+	}
+	maxPAMDist := options.MaxPAMDist
+	if maxPAMDist == 0 {
+		maxPAMDist = cutHeight
+	}
+
+	merges := 0
+	for merges < len(order) && heights[order[merges]] <= cutHeight {
+		merges++
+	}
+	if merges < minModuleSize {
+		return make([]int, n)
+	}
+
+	// Leafs merged into a composite branch (or into a basic branch that was
+	// then merged into one) record that branch so the PAM stage can respect the
+	// dendrogram.
+	branches := make([]hybridBranch, 0)
+	failed := make([]int, 0)
+	nodeBranch := make([]int, 2*n-1)
+	onBranch := make([]int, n)
+	for i := range onBranch {
+		onBranch[i] = -1
+	}
+	basics := func(i int) []int {
+		if branches[i].basic {
+			return []int{i}
+		}
+		return branches[i].basics
+	}
+	for _, index := range order[:merges] {
+		cluster := dendrogram[index]
+		height := heights[index]
+
+		a, b := cluster.Leafa, cluster.Leafb
+		if a < n && b < n {
+			branches = append(branches, hybridBranch{basic: true, leafs: []int{a, b}, size: 2, topBasic: true})
+			nodeBranch[cluster.Node] = len(branches) - 1
+		} else if a < n || b < n {
+			leaf, node := a, b
+			if b < n {
+				leaf, node = b, a
+			}
+			current := nodeBranch[node]
+			if branches[current].basic {
+				branches[current].leafs = append(branches[current].leafs, leaf)
+			} else {
+				onBranch[leaf] = current
+			}
+			branches[current].size++
+			nodeBranch[cluster.Node] = current
+		} else {
+			// Ties in size keep the first child as the smaller branch.
+			small, large := nodeBranch[a], nodeBranch[b]
+			if branches[small].size > branches[large].size {
+				small, large = large, small
+			}
+
+			// A basic branch that is not a module at this height is merged into the
+			// other branch. It fails only on size when its core is tight enough and
+			// far enough below the merge.
+			fails := func(i int) (fail, failSize bool) {
+				if !branches[i].basic {
+					return false, false
+				}
+				scatter := coreScatter(branches[i].leafs, dist, minModuleSize)
+				badScatter := scatter > maxAbsCoreScatter
+				badGap := height-scatter < minAbsGap
+				fail = branches[i].size < minModuleSize || badScatter || badGap || height < minAbsSplitHeight
+				return fail, !badScatter && !badGap
+			}
+			doMerge, failSize := fails(small)
+			if !doMerge {
+				doMerge, failSize = fails(large)
+				if doMerge {
+					small, large = large, small
+				}
+			}
+
+			if doMerge {
+				branches[small].failSize = failSize
+				branches[small].topBasic = false
+				if failSize {
+					failed = append(failed, small)
+				}
+				if branches[large].basic {
+					branches[large].leafs = append(branches[large].leafs, branches[small].leafs...)
+				} else {
+					for _, leaf := range branches[small].leafs {
+						onBranch[leaf] = large
+					}
+				}
+				branches[large].size += branches[small].size
+				nodeBranch[cluster.Node] = large
+			} else {
+				if branches[large].basic && !branches[small].basic {
+					small, large = large, small
+				}
+				if branches[large].basic || (options.PAMStage && options.PAMRespectsDendro) {
+					composite := hybridBranch{
+						basics: append(append([]int{}, basics(small)...), basics(large)...),
+						size:   branches[small].size + branches[large].size,
+					}
+					branches = append(branches, composite)
+					nodeBranch[cluster.Node] = len(branches) - 1
+				} else {
+					branches[large].basics = append(branches[large].basics, basics(small)...)
+					branches[large].size += branches[small].size
+					nodeBranch[cluster.Node] = large
+				}
+			}
+		}
+	}
+
+	colors := make([]int, n)
+	branchLabels := make([]int, len(branches))
+	numLabels := 0
+	for i, b := range branches {
+		if b.topBasic && b.size >= minModuleSize {
+			numLabels++
+			branchLabels[i] = numLabels
+			for _, leaf := range b.leafs {
+				colors[leaf] = numLabels
+			}
+		}
+	}
+
+	if options.PAMStage && numLabels > 0 {
+		hybridPAM(dist, colors, numLabels, hybridPAMOptions{
+			branchLabels:         branchLabels,
+			branches:             branches,
+			failed:               failed,
+			maxPAMDist:           maxPAMDist,
+			onBranch:             onBranch,
+			respectsDendro:       options.PAMRespectsDendro,
+			respectSmallClusters: options.RespectSmallClusters,
+		})
+	}
+
+	modules := make([][]int, numLabels)
+	for leaf, color := range colors {
+		if color > 0 {
+			modules[color-1] = append(modules[color-1], leaf)
+		}
+	}
+	return labelModules(n, modules)
+}
+
+// coreScatter is the average distance between the leafs in the core of a
+// branch, i.e. the leafs merged into it first.
+func coreScatter(members []int, dist [][]float64, minModuleSize int) float64 {
+	coreSize := len(members)
+	baseCoreSize := (float64(minModuleSize) / 2) + 1
+	if baseCoreSize < float64(coreSize) {
+		coreSize = int(baseCoreSize + math.Sqrt(float64(coreSize)-baseCoreSize))
+	}
+	if coreSize < 2 {
+		return 0
+	}
+
+	core := members[:coreSize]
+	sum := float64(0)
+	for _, i := range core {
+		for _, j := range core {
+			sum += dist[i][j]
+		}
+	}
+	return sum / float64(coreSize*(coreSize-1))
+}
+
+// hybridPAMOptions holds the state of the hybrid cut needed by its PAM stage.
+// onBranch is the composite branch each leaf belongs to, or -1, and failed
+// lists the branches that were too small to be modules in the order they were
+// merged.
+type hybridPAMOptions struct {
+	branchLabels         []int
+	branches             []hybridBranch
+	failed               []int
+	maxPAMDist           float64
+	onBranch             []int
+	respectsDendro       bool
+	respectSmallClusters bool
+}
+
+// hybridPAM assigns unlabelled leafs to the module with the smallest average
+// distance, provided that distance is less than the module's diameter (the
+// largest average distance from a member to the other members) or the maximum
+// PAM distance. Branches that were too small to be modules are assigned first
+// and together, and their leafs are left unassigned when they are too far from
+// every module. Distances are to the modules as they were before this stage.
+func hybridPAM(dist [][]float64, colors []int, numLabels int, options hybridPAMOptions) {
+	n := len(colors)
+	initial := append([]int{}, colors...)
+	members := make([][]int, numLabels+1)
+	for leaf, color := range initial {
+		members[color] = append(members[color], leaf)
+	}
+	diameter := make([]float64, numLabels+1)
+	for color := 1; color <= numLabels; color++ {
+		if len(members[color]) < 2 {
+			continue
+		}
+		for _, i := range members[color] {
+			sum := float64(0)
+			for _, j := range members[color] {
+				sum += dist[i][j]
+			}
+			diameter[color] = math.Max(diameter[color], sum/float64(len(members[color])-1))
+		}
+	}
+
+	// candidates gets the labels of the modules a leaf can join.
+	allLabels := make([]int, numLabels)
+	for i := range allLabels {
+		allLabels[i] = i + 1
+	}
+	candidates := func(leaf int) []int {
+		if !options.respectsDendro {
+			return allLabels
+		}
+		if options.onBranch[leaf] < 0 {
+			return nil
+		}
+		labels := make([]int, 0)
+		for _, b := range options.branches[options.onBranch[leaf]].basics {
+			if options.branchLabels[b] > 0 {
+				labels = append(labels, options.branchLabels[b])
+			}
+		}
+		sort.Ints(labels)
+		return labels
+	}
+	nearest := func(leafs []int, labels []int) (label int, ok bool) {
+		best := math.MaxFloat64
+		for _, candidate := range labels {
+			sum := float64(0)
+			for _, i := range leafs {
+				for _, j := range members[candidate] {
+					sum += dist[i][j]
+				}
+			}
+			if average := sum / float64(len(leafs)*len(members[candidate])); average < best {
+				best = average
+				label = candidate
+			}
+		}
+		ok = label > 0 && (best < diameter[label] || best < options.maxPAMDist)
+		return
+	}
+
+	if options.respectSmallClusters {
+		smallLabels := make([]int, n)
+		for _, b := range options.failed {
+			for _, leaf := range options.branches[b].leafs {
+				smallLabels[leaf] = b + 1
+			}
+		}
+		groups := make(map[int][]int)
+		for leaf, label := range smallLabels {
+			if label > 0 && initial[leaf] == 0 {
+				groups[label] = append(groups[label], leaf)
+			}
+		}
+		keys := make([]int, 0, len(groups))
+		for key := range groups {
+			keys = append(keys, key)
+		}
+		sort.Ints(keys)
+		for _, key := range keys {
+			leafs := groups[key]
+			labels := candidates(leafs[0])
+			if len(labels) == 0 {
+				continue
+			}
+			label, ok := nearest(leafs, labels)
+			if !ok {
+				label = -1
+			}
+			for _, leaf := range leafs {
+				colors[leaf] = label
+			}
+		}
+	}
+
+	for leaf := 0; leaf < n; leaf++ {
+		if colors[leaf] != 0 {
+			continue
+		}
+		if label, ok := nearest([]int{leaf}, candidates(leaf)); ok {
+			colors[leaf] = label
+		}
+	}
+	for leaf, color := range colors {
+		if color < 0 {
+			colors[leaf] = 0
+		}
+	}
+}
+
+func dynamicTree(dend typedef.Dendrogram, options DynamicOptions) []int {
+	n := dend.NumLeafs()
+	heights := monotoneHeights(dend)
+	cutHeight := options.CutHeight
+	if cutHeight <= 0 {
+		cutHeight = 0.99
+	}
+
+	// Branches at the cut height are contiguous in leaf order and each large
+	// enough branch is split separately.
+	order := dendrogram.LeafOrder(dend)
+	gaps := leafGaps(dend, heights)
+	static := Height(dend, cutHeight)
+	modules := make([][]int, 0)
+	start := 0
+	for i := 1; i <= n; i++ {
+		if i == n || static[order[i]] != static[order[start]] {
+			if i-start >= options.MinModuleSize {
+				modules = append(modules, splitSegment(order, gaps, start, i, options.DeepSplit > 0, options.MinModuleSize)...)
+			}
+			start = i
+		}
+	}
+	return labelModules(n, modules)
+}
+
+// leafGaps gets the height at which each pair of adjacent leafs (in leaf
+// order) are joined.
+func leafGaps(dendrogram typedef.Dendrogram, heights []float64) []float64 {
+	n := dendrogram.NumLeafs()
+	gaps := make([]float64, n-1)
+	if n < 2 {
+		return gaps
+	}
+
+	sizes := dendrogram.Sizes()
+	size := func(node int) int {
+		if node < n {
+			return 1
+		}
+		return sizes[node-n]
+	}
+	start := make([]int, 2*n-1)
+	for i := len(dendrogram) - 1; i >= 0; i-- {
+		cluster := dendrogram[i]
+		start[cluster.Leafa] = start[cluster.Node]
+		start[cluster.Leafb] = start[cluster.Node] + size(cluster.Leafa)
+		gaps[start[cluster.Leafb]-1] = heights[i]
+	}
+	return gaps
+}
+
+// splitSegment splits the leafs between start and end (in leaf order) into
+// modules. The reference height is the mean gap between adjacent leafs, or
+// halfway between the mean and maximum gap when not splitting deeply, and the
+// segment is split into runs at every gap above it. Runs smaller than the
+// minimum module size are then joined, smallest first, to the neighbouring run
+// with the lower gap between them. When more than one run is left each is split
+// recursively, and otherwise the segment is a single module.
+func splitSegment(order []int, gaps []float64, start, end int, deepSplit bool, minModuleSize int) [][]int {
+	segment := [][]int{append([]int{}, order[start:end]...)}
+	if end-start < 2*minModuleSize || end-start < 2 {
+		return segment
+	}
+
+	segmentGaps := gaps[start : end-1]
+	mean := float64(0)
+	max := -math.MaxFloat64
+	for _, gap := range segmentGaps {
+		mean += gap
+		max = math.Max(max, gap)
+	}
+	mean /= float64(len(segmentGaps))
+	reference := mean
+	if !deepSplit {
+		reference = (mean + max) / 2
+	}
+
+	// Each run starts at a leaf and ends before the gap that splits it from the
+	// next run.
+	runs := make([][2]int, 0)
+	runStart := start
+	for i := start; i < end-1; i++ {
+		if gaps[i] > reference {
+			runs = append(runs, [2]int{runStart, i + 1})
+			runStart = i + 1
+		}
+	}
+	runs = append(runs, [2]int{runStart, end})
+
+	for len(runs) > 1 {
+		smallest := -1
+		for i, run := range runs {
+			if size := run[1] - run[0]; size < minModuleSize && (smallest < 0 || size < runs[smallest][1]-runs[smallest][0]) {
+				smallest = i
+			}
+		}
+		if smallest < 0 {
+			break
+		}
+		neighbour := smallest - 1
+		if smallest == 0 || (smallest < len(runs)-1 && gaps[runs[smallest][1]-1] < gaps[runs[smallest][0]-1]) {
+			neighbour = smallest + 1
+		}
+		left, right := smallest, neighbour
+		if neighbour < smallest {
+			left, right = neighbour, smallest
+		}
+		runs[left][1] = runs[right][1]
+		runs = append(runs[:right], runs[right+1:]...)
+	}
+	if len(runs) < 2 {
+		return segment
+	}
+
+	modules := make([][]int, 0)
+	for _, run := range runs {
+		modules = append(modules, splitSegment(order, gaps, run[0], run[1], deepSplit, minModuleSize)...)
+	}
+	return modules
+}
+
+// labelModules numbers modules from 1 in order of decreasing size. Leafs not
+// in a module have a label of 0.
+func labelModules(n int, modules [][]int) []int {
+	sort.SliceStable(modules, func(i, j int) bool {
+		return len(modules[i]) > len(modules[j])
+	})
+	labels := make([]int, n)
+	for i, members := range modules {
+		for _, leaf := range members {
+			labels[leaf] = i + 1
+		}
+	}
+	return labels
+}
@@ -0,0 +1,130 @@
+package cut
+
+import (
+	"testing"
+
+	"github.com/knightjdr/hclust/cluster"
+	"github.com/knightjdr/hclust/distance"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamic(t *testing.T) {
+	// Four groups of points, two of which (near 5 and 6) are close to each other,
+	// and an outlier at 20.
+	points := [][]float64{
+		{0}, {5}, {0.1}, {10}, {5.2}, {0.3}, {10.1}, {5.1},
+		{0.2}, {20}, {10.3}, {6.3}, {6.15}, {6.2}, {10.2}, {6.25},
+	}
+	dist := distance.Distance(points, "euclidean", false)
+	dendrogram, _ := cluster.Cluster(dist, "average")
+	options := DefaultDynamicOptions()
+	options.MinModuleSize = 3
+
+	// TEST1: hybrid mode without deep splitting joins the groups near 5 and 6.
+	options.DeepSplit = 0
+	labels, err := Dynamic(dendrogram, dist, "hybrid", options)
+	assert.Nil(t, err, "Should not return an error for hybrid mode")
+	assert.Equal(t, []int{3, 1, 3, 2, 1, 3, 2, 1, 3, 0, 2, 1, 1, 1, 2, 1}, labels, "Should find modules in hybrid mode")
+
+	// TEST2: hybrid mode with deep splitting separates the groups near 5 and 6.
+	options.DeepSplit = 3
+	labels, _ = Dynamic(dendrogram, dist, "hybrid", options)
+	assert.Equal(t, []int{3, 4, 3, 2, 4, 3, 2, 4, 3, 0, 2, 1, 1, 1, 2, 1}, labels, "Should split modules deeply in hybrid mode")
+
+	// TEST3: the PAM stage assigns the outlier when it need not respect the dendrogram.
+	options.PAMRespectsDendro = false
+	labels, _ = Dynamic(dendrogram, dist, "hybrid", options)
+	assert.Equal(t, []int{3, 4, 3, 1, 4, 3, 1, 4, 3, 1, 1, 2, 2, 2, 1, 2}, labels, "Should assign outlier in PAM stage")
+
+	// TEST4: tree mode with the default cut height of 0.99.
+	labels, err = Dynamic(dendrogram, nil, "tree", options)
+	assert.Nil(t, err, "Should not return an error for tree mode")
+	assert.Equal(t, []int{3, 4, 3, 1, 4, 3, 1, 4, 3, 0, 1, 2, 2, 2, 1, 2}, labels, "Should find modules in tree mode")
+
+	// TEST5: modules smaller than the minimum size are unassigned.
+	options.MinModuleSize = 4
+	labels, _ = Dynamic(dendrogram, nil, "tree", options)
+	assert.Equal(t, []int{3, 0, 3, 1, 0, 3, 1, 0, 3, 0, 1, 2, 2, 2, 1, 2}, labels, "Should not assign leafs in groups below the minimum size")
+
+	// TEST6: a minimum split height above the merge of the groups near 5 and 6
+	// keeps them together, whether given as a fraction or as a height.
+	options = DefaultDynamicOptions()
+	options.MinModuleSize = 3
+	options.DeepSplit = 3
+	options.MinSplitHeight = 0.1
+	joined := []int{3, 1, 3, 2, 1, 3, 2, 1, 3, 0, 2, 1, 1, 1, 2, 1}
+	labels, _ = Dynamic(dendrogram, dist, "hybrid", options)
+	assert.Equal(t, joined, labels, "Should merge branches below the relative minimum split height")
+	options.MinSplitHeight = 0
+	options.MinAbsSplitHeight = 1.5
+	labels, _ = Dynamic(dendrogram, dist, "hybrid", options)
+	assert.Equal(t, joined, labels, "Should merge branches below the absolute minimum split height")
+
+	// TEST7: a larger minimum gap also keeps the groups near 5 and 6 together.
+	options.MinAbsSplitHeight = 0
+	options.MinGap = 0.1
+	labels, _ = Dynamic(dendrogram, dist, "hybrid", options)
+	assert.Equal(t, joined, labels, "Should merge branches with a small gap")
+
+	// TEST8: the PAM stage only assigns the outlier within the maximum distance.
+	options.MinGap = 0
+	options.PAMRespectsDendro = false
+	options.MaxPAMDist = 5
+	labels, _ = Dynamic(dendrogram, dist, "hybrid", options)
+	assert.Equal(t, []int{3, 4, 3, 2, 4, 3, 2, 4, 3, 0, 2, 1, 1, 1, 2, 1}, labels, "Should not assign outlier beyond the maximum PAM distance")
+
+	// TEST9: tree mode with a cut height above the outlier only splits the
+	// outlier from the other leafs when splitting deeply, and it is then joined
+	// to its neighbouring run.
+	options = DefaultDynamicOptions()
+	options.MinModuleSize = 3
+	options.CutHeight = 30
+	options.DeepSplit = 0
+	labels, _ = Dynamic(dendrogram, nil, "tree", options)
+	assert.Equal(t, []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, labels, "Should not split deeply in tree mode")
+	options.DeepSplit = 1
+	labels, _ = Dynamic(dendrogram, nil, "tree", options)
+	assert.Equal(t, []int{3, 4, 3, 1, 4, 3, 1, 4, 3, 1, 1, 2, 2, 2, 1, 2}, labels, "Should split deeply in tree mode")
+
+	// TEST10: invalid options.
+	options = DefaultDynamicOptions()
+	options.DeepSplit = 5
+	_, err = Dynamic(dendrogram, dist, "hybrid", options)
+	assert.NotNil(t, err, "Should return an error for invalid deepSplit")
+	options = DefaultDynamicOptions()
+	_, err = Dynamic(dendrogram, dist[:3], "hybrid", options)
+	assert.NotNil(t, err, "Should return an error when distance matrix does not match dendrogram")
+	_, err = Dynamic(dendrogram, dist, "static", options)
+	assert.NotNil(t, err, "Should return an error for unknown method")
+}
+
+func TestDynamicSmallClusters(t *testing.T) {
+	// Two groups of four points and a tight pair far from both. The pair is too
+	// small to be a module and its points are individually closer to different
+	// groups, but together they are closer to the group near (2, 0).
+	points := [][]float64{
+		{0, 0}, {0.1, 0.1}, {0, 0.2}, {0.2, 0},
+		{2, 0}, {2.1, 0.1}, {2, 0.2}, {2.2, 0},
+		{0.8, 5}, {1.4, 5},
+	}
+	dist := distance.Distance(points, "euclidean", false)
+	dendrogram, _ := cluster.Cluster(dist, "average")
+	options := DefaultDynamicOptions()
+	options.CutHeight = 100
+	options.MaxPAMDist = 10
+	options.MinModuleSize = 3
+
+	// TEST1: the pair is assigned as a group.
+	labels, _ := Dynamic(dendrogram, dist, "hybrid", options)
+	assert.Equal(t, []int{2, 2, 2, 2, 1, 1, 1, 1, 1, 1}, labels, "Should assign small cluster as a group")
+
+	// TEST2: the points in the pair are assigned individually.
+	options.RespectSmallClusters = false
+	labels, _ = Dynamic(dendrogram, dist, "hybrid", options)
+	assert.Equal(t, []int{1, 1, 1, 1, 2, 2, 2, 2, 1, 2}, labels, "Should assign points individually")
+
+	// TEST3: the pair is left unassigned when the maximum PAM distance is small.
+	options.MaxPAMDist = 1
+	labels, _ = Dynamic(dendrogram, dist, "hybrid", options)
+	assert.Equal(t, []int{1, 1, 1, 1, 2, 2, 2, 2, 0, 0}, labels, "Should not assign distant points")
+}
@@ -0,0 +1,39 @@
+package cut
+
+import (
+	"math"
+
+	"github.com/knightjdr/hclust/dendrogram"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// Inconsistent cuts a dendrogram so that no node in a cluster has an
+// inconsistency coefficient (calculated to depth) greater than the threshold,
+// as with scipy's fcluster with the inconsistent criterion. Leafs are labelled
+// as with K.
+func Inconsistent(dend typedef.Dendrogram, threshold float64, depth int) (labels []int, err error) {
+	stats, err := dendrogram.Inconsistent(dend, depth)
+	if err != nil {
+		return
+	}
+
+	// Find the largest coefficient at or below each node. Nodes whose largest
+	// coefficient is within the threshold are merged.
+	n := dend.NumLeafs()
+	maxCoefficient := make([]float64, len(dend))
+	merges := make([]int, 0)
+	for i, cluster := range dend {
+		maxCoefficient[i] = stats[i].Coefficient
+		for _, child := range []int{cluster.Leafa, cluster.Leafb} {
+			if child >= n {
+				maxCoefficient[i] = math.Max(maxCoefficient[i], maxCoefficient[child-n])
+			}
+		}
+		if maxCoefficient[i] <= threshold {
+			merges = append(merges, i)
+		}
+	}
+
+	labels = cutMerges(dend, merges)
+	return
+}
@@ -0,0 +1,28 @@
+package cut
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInconsistent(t *testing.T) {
+	// Coefficients at depth 2 are 0, 0.7071, 0 and 1.0277.
+
+	// TEST1: threshold below the top node's coefficient.
+	labels, err := Inconsistent(testDendrogram, 0.8, 2)
+	assert.Nil(t, err, "Should not return an error for a valid depth")
+	assert.Equal(t, []int{1, 1, 2, 2, 1}, labels, "Should not merge nodes above the threshold")
+
+	// TEST2: threshold below every non-zero coefficient.
+	labels, _ = Inconsistent(testDendrogram, 0.5, 2)
+	assert.Equal(t, []int{1, 2, 3, 3, 1}, labels, "Should not merge nodes with a descendant above the threshold")
+
+	// TEST3: threshold above every coefficient.
+	labels, _ = Inconsistent(testDendrogram, 2, 2)
+	assert.Equal(t, []int{1, 1, 1, 1, 1}, labels, "Should return a single cluster")
+
+	// TEST4: invalid depth.
+	_, err = Inconsistent(testDendrogram, 1, 0)
+	assert.NotNil(t, err, "Should return an error for an invalid depth")
+}
@@ -0,0 +1,92 @@
+package dendrogram
+
+import (
+	"errors"
+	"math"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// Cophenetic gets the cophenetic distance matrix for a dendrogram. The
+// cophenetic distance between two leafs is the merge height of the lowest node
+// containing both of them.
+func Cophenetic(dendrogram typedef.Dendrogram) [][]float64 {
+	n := dendrogram.NumLeafs()
+	heights := dendrogram.Heights()
+
+	cophenetic := make([][]float64, n)
+	for i := range cophenetic {
+		cophenetic[i] = make([]float64, n)
+	}
+
+	// Track the leafs under each node and set the distance between every pair of
+	// leafs on opposite sides of a node to the node's height.
+	members := make([][]int, 2*n-1)
+	for i := 0; i < n; i++ {
+		members[i] = []int{i}
+	}
+	for i, cluster := range dendrogram {
+		for _, a := range members[cluster.Leafa] {
+			for _, b := range members[cluster.Leafb] {
+				cophenetic[a][b] = heights[i]
+				cophenetic[b][a] = heights[i]
+			}
+		}
+		members[cluster.Node] = append(members[cluster.Leafa], members[cluster.Leafb]...)
+		members[cluster.Leafa] = nil
+		members[cluster.Leafb] = nil
+	}
+
+	return cophenetic
+}
+
+// CopheneticCorrelation gets the Pearson correlation between the cophenetic
+// distances of a dendrogram and the distance matrix used to create it. The
+// correlation is undefined, and an error is returned, when all of the distances
+// or all of the merge heights are equal.
+func CopheneticCorrelation(dendrogram typedef.Dendrogram, dist [][]float64) (correlation float64, err error) {
+	n := dendrogram.NumLeafs()
+	if len(dist) != n {
+		err = errors.New("The distance matrix must have one row for each leaf")
+		return
+	}
+	for _, row := range dist {
+		if len(row) != n {
+			err = errors.New("The distance matrix must be square")
+			return
+		}
+	}
+	if n < 3 {
+		err = errors.New("At least three leafs are required for the cophenetic correlation")
+		return
+	}
+
+	cophenetic := Cophenetic(dendrogram)
+	pairs := float64(n*(n-1)) / 2
+	var meanCophenetic, meanDist float64
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			meanCophenetic += cophenetic[i][j]
+			meanDist += dist[i][j]
+		}
+	}
+	meanCophenetic /= pairs
+	meanDist /= pairs
+
+	var covariance, varCophenetic, varDist float64
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			x := cophenetic[i][j] - meanCophenetic
+			y := dist[i][j] - meanDist
+			covariance += x * y
+			varCophenetic += x * x
+			varDist += y * y
+		}
+	}
+	if varCophenetic == 0 || varDist == 0 {
+		err = errors.New("The cophenetic correlation is undefined when all distances or all merge heights are equal")
+		return
+	}
+	correlation = covariance / math.Sqrt(varCophenetic*varDist)
+	return
+}
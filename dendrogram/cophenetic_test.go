@@ -0,0 +1,74 @@
+package dendrogram
+
+import (
+	"testing"
+
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCophenetic(t *testing.T) {
+	// TEST1: cophenetic matrix from merge heights.
+	dendrogram := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5, Height: 2, Size: 2},
+		{Leafa: 5, Leafb: 1, Lengtha: 2.95, Lengthb: 3.95, Node: 6, Height: 7.9, Size: 3},
+		{Leafa: 3, Leafb: 2, Lengtha: 6.1, Lengthb: 6.1, Node: 7, Height: 12.2, Size: 2},
+		{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.56, Node: 8, Height: 17.32, Size: 5},
+	}
+	want := [][]float64{
+		{0, 7.9, 17.32, 17.32, 2},
+		{7.9, 0, 17.32, 17.32, 7.9},
+		{17.32, 17.32, 0, 12.2, 17.32},
+		{17.32, 17.32, 12.2, 0, 17.32},
+		{2, 7.9, 17.32, 17.32, 0},
+	}
+	assert.Equal(t, want, Cophenetic(dendrogram), "Should calculate cophenetic matrix")
+
+	// TEST2: a single leaf.
+	assert.Equal(t, [][]float64{{0}}, Cophenetic(typedef.Dendrogram{}), "Should calculate cophenetic matrix for a single leaf")
+}
+
+func TestCopheneticCorrelation(t *testing.T) {
+	dendrogram := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 1, Node: 3, Height: 1, Size: 2},
+		{Leafa: 3, Leafb: 2, Node: 4, Height: 3, Size: 3},
+	}
+
+	// TEST1: distances matching the dendrogram exactly.
+	dist := [][]float64{
+		{0, 1, 3},
+		{1, 0, 3},
+		{3, 3, 0},
+	}
+	correlation, err := CopheneticCorrelation(dendrogram, dist)
+	assert.Nil(t, err, "Should not return an error for a valid distance matrix")
+	assert.InDelta(t, 1, correlation, 0.0001, "Should have a correlation of 1 when distances match the dendrogram")
+
+	// TEST2: distances that do not match the dendrogram exactly.
+	dist = [][]float64{
+		{0, 1, 2},
+		{1, 0, 4},
+		{2, 4, 0},
+	}
+	correlation, _ = CopheneticCorrelation(dendrogram, dist)
+	assert.InDelta(t, 0.7559, correlation, 0.0001, "Should calculate cophenetic correlation")
+
+	// TEST3: distance matrix does not match dendrogram.
+	_, err = CopheneticCorrelation(dendrogram, dist[:2])
+	assert.NotNil(t, err, "Should return an error when distance matrix does not match dendrogram")
+
+	// TEST4: the correlation is undefined without any variance.
+	equal := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 1, Node: 3, Height: 2, Size: 2},
+		{Leafa: 3, Leafb: 2, Node: 4, Height: 2, Size: 3},
+	}
+	_, err = CopheneticCorrelation(equal, dist)
+	assert.NotNil(t, err, "Should return an error when all merge heights are equal")
+	dist = [][]float64{
+		{0, 2, 2},
+		{2, 0, 2},
+		{2, 2, 0},
+	}
+	_, err = CopheneticCorrelation(dendrogram, dist)
+	assert.NotNil(t, err, "Should return an error when all distances are equal")
+}
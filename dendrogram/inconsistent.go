@@ -0,0 +1,70 @@
+package dendrogram
+
+import (
+	"errors"
+	"math"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// Inconsistency holds the statistics for the merge heights of a node and the
+// nodes below it.
+type Inconsistency struct {
+	Mean        float64
+	StdDev      float64
+	Count       int
+	Coefficient float64
+}
+
+// Inconsistent calculates the inconsistency statistics for each node in a
+// dendrogram, as with scipy's inconsistent. For each node the mean and
+// (sample) standard deviation are calculated from the merge heights of the
+// node and the internal nodes up to depth levels below it (a depth of 1 only
+// includes the node). The coefficient is the number of standard deviations the
+// node's height is above the mean, or 0 when the standard deviation is 0.
+func Inconsistent(dendrogram typedef.Dendrogram, depth int) (stats []Inconsistency, err error) {
+	if depth < 1 {
+		err = errors.New("The depth must be at least 1")
+		return
+	}
+
+	n := dendrogram.NumLeafs()
+	heights := dendrogram.Heights()
+	stats = make([]Inconsistency, len(dendrogram))
+	for i, cluster := range dendrogram {
+		type level struct {
+			node  int
+			depth int
+		}
+
+		var sum, sumSquares float64
+		count := 0
+		stack := []level{{node: cluster.Node, depth: 1}}
+		for len(stack) > 0 {
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			index := current.node - n
+			sum += heights[index]
+			sumSquares += heights[index] * heights[index]
+			count++
+			if current.depth < depth {
+				for _, child := range []int{dendrogram[index].Leafa, dendrogram[index].Leafb} {
+					if child >= n {
+						stack = append(stack, level{node: child, depth: current.depth + 1})
+					}
+				}
+			}
+		}
+
+		stats[i].Mean = sum / float64(count)
+		stats[i].Count = count
+		if count > 1 {
+			variance := (sumSquares - (sum * sum / float64(count))) / float64(count-1)
+			stats[i].StdDev = math.Sqrt(math.Max(variance, 0))
+		}
+		if stats[i].StdDev > 0 {
+			stats[i].Coefficient = (heights[i] - stats[i].Mean) / stats[i].StdDev
+		}
+	}
+	return
+}
@@ -0,0 +1,41 @@
+package dendrogram
+
+import (
+	"testing"
+
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInconsistent(t *testing.T) {
+	dendrogram := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 4, Node: 5, Height: 2, Size: 2},
+		{Leafa: 5, Leafb: 1, Node: 6, Height: 7.9, Size: 3},
+		{Leafa: 3, Leafb: 2, Node: 7, Height: 12.2, Size: 2},
+		{Leafa: 6, Leafb: 7, Node: 8, Height: 17.32, Size: 5},
+	}
+
+	// TEST1: depth of 2.
+	stats, err := Inconsistent(dendrogram, 2)
+	want := []Inconsistency{
+		{Mean: 2, StdDev: 0, Count: 1, Coefficient: 0},
+		{Mean: 4.95, StdDev: 4.1719, Count: 2, Coefficient: 0.7071},
+		{Mean: 12.2, StdDev: 0, Count: 1, Coefficient: 0},
+		{Mean: 12.4733, StdDev: 4.7159, Count: 3, Coefficient: 1.0277},
+	}
+	assert.Nil(t, err, "Should not return an error for a valid depth")
+	for i, stat := range stats {
+		assert.InDelta(t, want[i].Mean, stat.Mean, 0.0001, "Should calculate mean height")
+		assert.InDelta(t, want[i].StdDev, stat.StdDev, 0.0001, "Should calculate standard deviation of heights")
+		assert.Equal(t, want[i].Count, stat.Count, "Should count nodes")
+		assert.InDelta(t, want[i].Coefficient, stat.Coefficient, 0.0001, "Should calculate inconsistency coefficient")
+	}
+
+	// TEST2: depth of 3 includes all nodes below the top node.
+	stats, _ = Inconsistent(dendrogram, 3)
+	assert.Equal(t, 4, stats[3].Count, "Should include nodes up to depth")
+
+	// TEST3: invalid depth.
+	_, err = Inconsistent(dendrogram, 0)
+	assert.NotNil(t, err, "Should return an error for a depth less than 1")
+}
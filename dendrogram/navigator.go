@@ -0,0 +1,335 @@
+package dendrogram
+
+import (
+	"errors"
+	"math/bits"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// Navigator answers structural queries about a dendrogram: parents, children,
+// depths, the leafs under a node and lowest common ancestors. Leafs are
+// numbered 0 to n-1 and internal nodes n to 2n-2, as in the dendrogram, and
+// leafs can also be looked up by name.
+type Navigator struct {
+	children  [][2]int
+	depth     []int
+	first     []int
+	leafEnd   []int
+	leafStart []int
+	n         int
+	nameIndex map[string]int
+	names     []string
+	order     []int
+	parent    []int
+	root      int
+	sparse    [][]int
+}
+
+// Iterator steps through the nodes of a tree.
+type Iterator struct {
+	next func() (int, bool)
+}
+
+// Next returns the next node. ok is false when there are no nodes left.
+func (it *Iterator) Next() (node int, ok bool) {
+	return it.next()
+}
+
+// NewNavigator creates a Navigator for a dendrogram. Names are optional (nil)
+// but if supplied there must be one unique name per leaf.
+func NewNavigator(dendrogram typedef.Dendrogram, names []string) (nav *Navigator, err error) {
+	n := dendrogram.NumLeafs()
+	if names != nil && len(names) != n {
+		err = errors.New("The names vector must have the same dimension as the leaf number")
+		return
+	}
+	nameIndex := make(map[string]int, len(names))
+	for i, name := range names {
+		if _, ok := nameIndex[name]; ok {
+			err = errors.New("Leaf names must be unique")
+			return
+		}
+		nameIndex[name] = i
+	}
+
+	nav = &Navigator{
+		children:  make([][2]int, n-1),
+		depth:     make([]int, 2*n-1),
+		first:     make([]int, 2*n-1),
+		leafEnd:   make([]int, 2*n-1),
+		leafStart: make([]int, 2*n-1),
+		n:         n,
+		nameIndex: nameIndex,
+		names:     names,
+		parent:    make([]int, 2*n-1),
+		root:      2*n - 2,
+	}
+	for i := range nav.parent {
+		nav.parent[i] = -1
+	}
+	for _, cluster := range dendrogram {
+		nav.children[cluster.Node-n] = [2]int{cluster.Leafa, cluster.Leafb}
+		nav.parent[cluster.Leafa] = cluster.Node
+		nav.parent[cluster.Leafb] = cluster.Node
+	}
+
+	nav.traverse()
+	nav.buildSparseTable()
+	return
+}
+
+// traverse walks the tree depth first from the root, recording the depth of
+// each node, the leaf order, the range of leafs (in leaf order) under each node
+// and the Euler tour used for lowest common ancestor queries.
+func (nav *Navigator) traverse() {
+	nav.order = make([]int, 0, nav.n)
+	euler := make([]int, 0, (4*nav.n)-3)
+
+	type visit struct {
+		node  int
+		child int
+	}
+	stack := []visit{{node: nav.root}}
+	nav.first[nav.root] = 0
+	euler = append(euler, nav.root)
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		node := top.node
+		if node < nav.n {
+			nav.order = append(nav.order, node)
+			nav.leafStart[node] = len(nav.order) - 1
+			nav.leafEnd[node] = len(nav.order)
+			stack = stack[:len(stack)-1]
+		} else if top.child < 2 {
+			child := nav.children[node-nav.n][top.child]
+			if top.child == 0 {
+				nav.leafStart[node] = len(nav.order)
+			}
+			top.child++
+			nav.depth[child] = nav.depth[node] + 1
+			nav.first[child] = len(euler)
+			euler = append(euler, child)
+			stack = append(stack, visit{node: child})
+			continue
+		} else {
+			nav.leafEnd[node] = len(nav.order)
+			stack = stack[:len(stack)-1]
+		}
+
+		// Return to the parent in the Euler tour.
+		if len(stack) > 0 {
+			euler = append(euler, stack[len(stack)-1].node)
+		}
+	}
+	nav.sparse = [][]int{euler}
+}
+
+// buildSparseTable precomputes the shallowest node in every power-of-two
+// window of the Euler tour so lowest common ancestors can be found in O(1).
+func (nav *Navigator) buildSparseTable() {
+	euler := nav.sparse[0]
+	for width := 2; width <= len(euler); width *= 2 {
+		previous := nav.sparse[len(nav.sparse)-1]
+		level := make([]int, len(euler)-width+1)
+		for i := range level {
+			a, b := previous[i], previous[i+(width/2)]
+			if nav.depth[b] < nav.depth[a] {
+				a = b
+			}
+			level[i] = a
+		}
+		nav.sparse = append(nav.sparse, level)
+	}
+}
+
+func (nav *Navigator) valid(node int) bool {
+	return node >= 0 && node <= nav.root
+}
+
+// NumLeafs returns the number of leafs in the tree.
+func (nav *Navigator) NumLeafs() int {
+	return nav.n
+}
+
+// Root returns the top node of the tree.
+func (nav *Navigator) Root() int {
+	return nav.root
+}
+
+// IsLeaf reports whether a node is a leaf.
+func (nav *Navigator) IsLeaf(node int) bool {
+	return node >= 0 && node < nav.n
+}
+
+// Parent returns the parent of a node. The root and unknown nodes return -1.
+func (nav *Navigator) Parent(node int) int {
+	if !nav.valid(node) {
+		return -1
+	}
+	return nav.parent[node]
+}
+
+// Children returns the left and right children of a node. Leafs and unknown
+// nodes return -1 for both.
+func (nav *Navigator) Children(node int) (a, b int) {
+	if !nav.valid(node) || nav.IsLeaf(node) {
+		return -1, -1
+	}
+	children := nav.children[node-nav.n]
+	return children[0], children[1]
+}
+
+// Depth returns the number of edges between a node and the root. Unknown nodes
+// return -1.
+func (nav *Navigator) Depth(node int) int {
+	if !nav.valid(node) {
+		return -1
+	}
+	return nav.depth[node]
+}
+
+// Leafs returns the leafs under a node from left to right. Unknown nodes return
+// nil.
+func (nav *Navigator) Leafs(node int) []int {
+	if !nav.valid(node) {
+		return nil
+	}
+	return append([]int{}, nav.order[nav.leafStart[node]:nav.leafEnd[node]]...)
+}
+
+// LeafOrder returns every leaf from left to right.
+func (nav *Navigator) LeafOrder() []int {
+	return append([]int{}, nav.order...)
+}
+
+// LCA returns the lowest common ancestor of two nodes. Unknown nodes return -1.
+func (nav *Navigator) LCA(a, b int) int {
+	if !nav.valid(a) || !nav.valid(b) {
+		return -1
+	}
+	left, right := nav.first[a], nav.first[b]
+	if left > right {
+		left, right = right, left
+	}
+	level := bits.Len(uint(right-left+1)) - 1
+	x := nav.sparse[level][left]
+	y := nav.sparse[level][right-(1<<uint(level))+1]
+	if nav.depth[y] < nav.depth[x] {
+		return y
+	}
+	return x
+}
+
+// Leaf returns the leaf with a name.
+func (nav *Navigator) Leaf(name string) (leaf int, err error) {
+	leaf, ok := nav.nameIndex[name]
+	if !ok {
+		leaf = -1
+		err = errors.New("Unknown leaf name")
+	}
+	return
+}
+
+// Name returns the name of a leaf, or an empty string if the leaf is unknown
+// or the tree has no names.
+func (nav *Navigator) Name(leaf int) string {
+	if nav.names == nil || !nav.IsLeaf(leaf) {
+		return ""
+	}
+	return nav.names[leaf]
+}
+
+// LeafNames returns the names of the leafs under a node from left to right.
+func (nav *Navigator) LeafNames(node int) []string {
+	if nav.names == nil || !nav.valid(node) {
+		return nil
+	}
+	leafs := nav.Leafs(node)
+	names := make([]string, len(leafs))
+	for i, leaf := range leafs {
+		names[i] = nav.names[leaf]
+	}
+	return names
+}
+
+// LCAByName returns the lowest common ancestor of two leafs given by name.
+func (nav *Navigator) LCAByName(a, b string) (node int, err error) {
+	leafa, err := nav.Leaf(a)
+	if err != nil {
+		return -1, err
+	}
+	leafb, err := nav.Leaf(b)
+	if err != nil {
+		return -1, err
+	}
+	return nav.LCA(leafa, leafb), nil
+}
+
+// PreOrder iterates through the subtree under a node, visiting each node before
+// its children and left children before right children.
+func (nav *Navigator) PreOrder(node int) *Iterator {
+	stack := make([]int, 0)
+	if nav.valid(node) {
+		stack = append(stack, node)
+	}
+	return &Iterator{next: func() (int, bool) {
+		if len(stack) == 0 {
+			return -1, false
+		}
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !nav.IsLeaf(current) {
+			children := nav.children[current-nav.n]
+			stack = append(stack, children[1], children[0])
+		}
+		return current, true
+	}}
+}
+
+// PostOrder iterates through the subtree under a node, visiting each node after
+// its children and left children before right children.
+func (nav *Navigator) PostOrder(node int) *Iterator {
+	type visit struct {
+		node     int
+		expanded bool
+	}
+	stack := make([]visit, 0)
+	if nav.valid(node) {
+		stack = append(stack, visit{node: node})
+	}
+	return &Iterator{next: func() (int, bool) {
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if nav.IsLeaf(top.node) || top.expanded {
+				stack = stack[:len(stack)-1]
+				return top.node, true
+			}
+			stack[len(stack)-1].expanded = true
+			children := nav.children[top.node-nav.n]
+			stack = append(stack, visit{node: children[1]}, visit{node: children[0]})
+		}
+		return -1, false
+	}}
+}
+
+// LevelOrder iterates through the subtree under a node one depth at a time,
+// from left to right within each depth.
+func (nav *Navigator) LevelOrder(node int) *Iterator {
+	queue := make([]int, 0)
+	if nav.valid(node) {
+		queue = append(queue, node)
+	}
+	return &Iterator{next: func() (int, bool) {
+		if len(queue) == 0 {
+			return -1, false
+		}
+		current := queue[0]
+		queue = queue[1:]
+		if !nav.IsLeaf(current) {
+			children := nav.children[current-nav.n]
+			queue = append(queue, children[0], children[1])
+		}
+		return current, true
+	}}
+}
@@ -0,0 +1,107 @@
+package dendrogram
+
+import (
+	"testing"
+
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+func collect(it *Iterator) []int {
+	nodes := make([]int, 0)
+	for node, ok := it.Next(); ok; node, ok = it.Next() {
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func TestNavigator(t *testing.T) {
+	dendrogram := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5, Height: 2, Size: 2},
+		{Leafa: 5, Leafb: 1, Lengtha: 2.95, Lengthb: 3.95, Node: 6, Height: 7.9, Size: 3},
+		{Leafa: 3, Leafb: 2, Lengtha: 6.1, Lengthb: 6.1, Node: 7, Height: 12.2, Size: 2},
+		{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.56, Node: 8, Height: 17.32, Size: 5},
+	}
+	names := []string{"a", "b", "c", "d", "e"}
+	nav, err := NewNavigator(dendrogram, names)
+	assert.Nil(t, err, "Should not return an error for valid input")
+
+	// TEST1: parents and children.
+	assert.Equal(t, 8, nav.Root(), "Should return root node")
+	assert.Equal(t, 5, nav.Parent(4), "Should return parent of leaf")
+	assert.Equal(t, -1, nav.Parent(8), "Root should not have a parent")
+	a, b := nav.Children(6)
+	assert.Equal(t, []int{5, 1}, []int{a, b}, "Should return children of node")
+	a, b = nav.Children(1)
+	assert.Equal(t, []int{-1, -1}, []int{a, b}, "Leaf should not have children")
+
+	// TEST2: depth.
+	assert.Equal(t, 0, nav.Depth(8), "Root should have a depth of 0")
+	assert.Equal(t, 3, nav.Depth(4), "Should return depth of leaf")
+	assert.Equal(t, -1, nav.Depth(9), "Unknown node should have a depth of -1")
+
+	// TEST3: leaf sets.
+	assert.Equal(t, []int{0, 4, 1}, nav.Leafs(6), "Should return leafs under node")
+	assert.Equal(t, []int{2}, nav.Leafs(2), "Leaf should return itself")
+	assert.Equal(t, []int{0, 4, 1, 3, 2}, nav.LeafOrder(), "Should return leaf order")
+	assert.Equal(t, []string{"d", "c"}, nav.LeafNames(7), "Should return leaf names under node")
+
+	// TEST4: lowest common ancestor.
+	assert.Equal(t, 5, nav.LCA(0, 4), "Should return LCA of two leafs")
+	assert.Equal(t, 6, nav.LCA(1, 4), "Should return LCA of leafs at different depths")
+	assert.Equal(t, 8, nav.LCA(2, 0), "Should return root as LCA of leafs in different branches")
+	assert.Equal(t, 6, nav.LCA(6, 0), "Node should be the LCA of itself and its descendant")
+	assert.Equal(t, 3, nav.LCA(3, 3), "Leaf should be its own LCA")
+	node, err := nav.LCAByName("b", "e")
+	assert.Nil(t, err, "Should not return an error for known names")
+	assert.Equal(t, 6, node, "Should return LCA by name")
+	_, err = nav.LCAByName("b", "z")
+	assert.NotNil(t, err, "Should return an error for unknown name")
+
+	// TEST5: lookup by name.
+	leaf, _ := nav.Leaf("d")
+	assert.Equal(t, 3, leaf, "Should return leaf by name")
+	assert.Equal(t, "c", nav.Name(2), "Should return name of leaf")
+
+	// TEST6: iterators.
+	assert.Equal(t, []int{8, 6, 5, 0, 4, 1, 7, 3, 2}, collect(nav.PreOrder(8)), "Should iterate in pre-order")
+	assert.Equal(t, []int{0, 4, 5, 1, 6, 3, 2, 7, 8}, collect(nav.PostOrder(8)), "Should iterate in post-order")
+	assert.Equal(t, []int{8, 6, 7, 5, 1, 3, 2, 0, 4}, collect(nav.LevelOrder(8)), "Should iterate in level-order")
+	assert.Equal(t, []int{7, 3, 2}, collect(nav.PreOrder(7)), "Should iterate over subtree")
+
+	// TEST7: invalid names.
+	_, err = NewNavigator(dendrogram, []string{"a", "b", "c", "d", "d"})
+	assert.NotNil(t, err, "Should return an error for duplicate names")
+	_, err = NewNavigator(dendrogram, []string{"a"})
+	assert.NotNil(t, err, "Should return an error when names do not match leafs")
+}
+
+func TestNavigatorLCA(t *testing.T) {
+	// TEST1: LCA matches a walk up the tree for every pair of nodes.
+	dendrogram := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 3, Node: 7},
+		{Leafa: 2, Leafb: 5, Node: 8},
+		{Leafa: 1, Leafb: 7, Node: 9},
+		{Leafa: 4, Leafb: 9, Node: 10},
+		{Leafa: 8, Leafb: 6, Node: 11},
+		{Leafa: 10, Leafb: 11, Node: 12},
+	}
+	nav, _ := NewNavigator(dendrogram, nil)
+	ancestors := func(node int) map[int]bool {
+		path := map[int]bool{node: true}
+		for node = nav.Parent(node); node >= 0; node = nav.Parent(node) {
+			path[node] = true
+		}
+		return path
+	}
+	for a := 0; a <= nav.Root(); a++ {
+		for b := 0; b <= nav.Root(); b++ {
+			want := b
+			path := ancestors(a)
+			for !path[want] {
+				want = nav.Parent(want)
+			}
+			assert.Equal(t, want, nav.LCA(a, b), "LCA should match walk up tree")
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package dendrogram
+
+import (
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// LeafOrder gets the order of leafs in a dendrogram from left to right, i.e.
+// the order used by tree.Create.
+func LeafOrder(dendrogram []typedef.SubCluster) []int {
+	n := len(dendrogram) + 1
+	order := make([]int, 0, n)
+	if len(dendrogram) == 0 {
+		return append(order, 0)
+	}
+
+	// Map nodes to dendrogram indices.
+	nodeIndex := make([]int, n-1)
+	for i, cluster := range dendrogram {
+		nodeIndex[cluster.Node-n] = i
+	}
+
+	// Descend from the top node, visiting left branches first.
+	stack := []int{dendrogram[n-2].Node}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node < n {
+			order = append(order, node)
+			continue
+		}
+		cluster := dendrogram[nodeIndex[node-n]]
+		stack = append(stack, cluster.Leafb, cluster.Leafa)
+	}
+
+	return order
+}
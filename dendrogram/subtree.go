@@ -0,0 +1,122 @@
+package dendrogram
+
+import (
+	"errors"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// Extract returns the subtree of a dendrogram spanning the named leafs. Nodes
+// left with a single child are collapsed, so each remaining node keeps its
+// merge height and branch lengths are adjusted to the remaining children. Leafs
+// are renumbered in their original relative order and the names of the
+// remaining leafs are returned in the new order. Leaf names must be unique and
+// at least one leaf must be extracted.
+func Extract(dendrogram typedef.Dendrogram, names []string, leafs []string) (subtree typedef.Dendrogram, subtreeNames []string, err error) {
+	keep, err := selectLeafs(dendrogram, names, leafs)
+	if err != nil {
+		return
+	}
+	subtree, subtreeNames, err = restrict(dendrogram, names, keep)
+	return
+}
+
+// Prune returns a dendrogram with the named leafs removed. Nodes left with a
+// single child are collapsed as with Extract. Leaf names must be unique and at
+// least one leaf must remain.
+func Prune(dendrogram typedef.Dendrogram, names []string, leafs []string) (pruned typedef.Dendrogram, prunedNames []string, err error) {
+	remove, err := selectLeafs(dendrogram, names, leafs)
+	if err != nil {
+		return
+	}
+	keep := make([]bool, len(remove))
+	for i := range remove {
+		keep[i] = !remove[i]
+	}
+	pruned, prunedNames, err = restrict(dendrogram, names, keep)
+	return
+}
+
+// selectLeafs marks the leafs with the requested names.
+func selectLeafs(dendrogram typedef.Dendrogram, names []string, leafs []string) (selected []bool, err error) {
+	if len(names) != dendrogram.NumLeafs() {
+		err = errors.New("The names vector must have the same dimension as the leaf number")
+		return
+	}
+
+	nameIndex := make(map[string]int, len(names))
+	for i, name := range names {
+		if _, ok := nameIndex[name]; ok {
+			err = errors.New("Leaf names must be unique")
+			return
+		}
+		nameIndex[name] = i
+	}
+	selected = make([]bool, len(names))
+	for _, name := range leafs {
+		i, ok := nameIndex[name]
+		if !ok {
+			err = errors.New("Unknown leaf name: " + name)
+			return
+		}
+		selected[i] = true
+	}
+	return
+}
+
+// restrict keeps the marked leafs, collapsing nodes with fewer than two
+// remaining children and renumbering leafs and nodes.
+func restrict(dendrogram typedef.Dendrogram, names []string, keep []bool) (subtree typedef.Dendrogram, subtreeNames []string, err error) {
+	n := dendrogram.NumLeafs()
+	heights := dendrogram.Heights()
+
+	// New node number, height and size for each original node. A collapsed node
+	// takes the values of its remaining child.
+	newNode := make([]int, 2*n-1)
+	newHeight := make([]float64, 2*n-1)
+	newSize := make([]int, 2*n-1)
+	subtreeNames = make([]string, 0)
+	for i := 0; i < n; i++ {
+		newNode[i] = -1
+		if keep[i] {
+			newNode[i] = len(subtreeNames)
+			newSize[i] = 1
+			subtreeNames = append(subtreeNames, names[i])
+		}
+	}
+	m := len(subtreeNames)
+	if m == 0 {
+		return nil, nil, errors.New("At least one leaf must remain in the dendrogram")
+	}
+
+	subtree = make(typedef.Dendrogram, 0)
+	for i, cluster := range dendrogram {
+		a, b := cluster.Leafa, cluster.Leafb
+		if newNode[a] < 0 && newNode[b] < 0 {
+			newNode[cluster.Node] = -1
+		} else if newNode[a] < 0 || newNode[b] < 0 {
+			child := a
+			if newNode[a] < 0 {
+				child = b
+			}
+			newNode[cluster.Node] = newNode[child]
+			newHeight[cluster.Node] = newHeight[child]
+			newSize[cluster.Node] = newSize[child]
+		} else {
+			node := m + len(subtree)
+			newNode[cluster.Node] = node
+			newHeight[cluster.Node] = heights[i]
+			newSize[cluster.Node] = newSize[a] + newSize[b]
+			subtree = append(subtree, typedef.SubCluster{
+				Leafa:   newNode[a],
+				Leafb:   newNode[b],
+				Lengtha: (heights[i] - newHeight[a]) / 2,
+				Lengthb: (heights[i] - newHeight[b]) / 2,
+				Node:    node,
+				Height:  heights[i],
+				Size:    newSize[cluster.Node],
+			})
+		}
+	}
+	return subtree, subtreeNames, nil
+}
@@ -0,0 +1,90 @@
+package dendrogram
+
+import (
+	"testing"
+
+	"github.com/knightjdr/hclust/tree"
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+var subtreeDendrogram = typedef.Dendrogram{
+	{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5, Height: 2, Size: 2},
+	{Leafa: 5, Leafb: 1, Lengtha: 2.95, Lengthb: 3.95, Node: 6, Height: 7.9, Size: 3},
+	{Leafa: 3, Leafb: 2, Lengtha: 6.1, Lengthb: 6.1, Node: 7, Height: 12.2, Size: 2},
+	{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.56, Node: 8, Height: 17.32, Size: 5},
+}
+
+var subtreeNames = []string{"a", "b", "c", "d", "e"}
+
+func TestExtract(t *testing.T) {
+	// TEST1: extract leafs from different branches.
+	subtree, names, err := Extract(subtreeDendrogram, subtreeNames, []string{"e", "b", "c"})
+	want := typedef.Dendrogram{
+		{Leafa: 2, Leafb: 0, Lengtha: 3.95, Lengthb: 3.95, Node: 3, Height: 7.9, Size: 2},
+		{Leafa: 3, Leafb: 1, Lengtha: 4.71, Lengthb: 8.66, Node: 4, Height: 17.32, Size: 3},
+	}
+	assert.Nil(t, err, "Should not return an error for valid names")
+	assert.Equal(t, []string{"b", "c", "e"}, names, "Should return names in new leaf order")
+	assert.Len(t, subtree, len(want), "Should collapse nodes with a single child")
+	for i, cluster := range subtree {
+		assert.Equal(t, want[i].Leafa, cluster.Leafa, "Should renumber left child")
+		assert.Equal(t, want[i].Leafb, cluster.Leafb, "Should renumber right child")
+		assert.InDelta(t, want[i].Lengtha, cluster.Lengtha, 0.0001, "Should adjust left branch length")
+		assert.InDelta(t, want[i].Lengthb, cluster.Lengthb, 0.0001, "Should adjust right branch length")
+		assert.Equal(t, want[i].Node, cluster.Node, "Should renumber node")
+		assert.Equal(t, want[i].Height, cluster.Height, "Should keep merge height")
+		assert.Equal(t, want[i].Size, cluster.Size, "Should recalculate size")
+	}
+
+	// TEST2: extract a single leaf.
+	subtree, names, _ = Extract(subtreeDendrogram, subtreeNames, []string{"d"})
+	assert.Equal(t, typedef.Dendrogram{}, subtree, "Should return an empty dendrogram for a single leaf")
+	assert.Equal(t, []string{"d"}, names, "Should return name of single leaf")
+
+	// TEST3: unknown name.
+	_, _, err = Extract(subtreeDendrogram, subtreeNames, []string{"a", "z"})
+	assert.NotNil(t, err, "Should return an error for an unknown name")
+
+	// TEST4: no leafs.
+	_, _, err = Extract(subtreeDendrogram, subtreeNames, []string{})
+	assert.NotNil(t, err, "Should return an error when no leafs are extracted")
+
+	// TEST5: duplicate leaf names.
+	_, _, err = Extract(subtreeDendrogram, []string{"a", "b", "c", "b", "e"}, []string{"a", "b"})
+	assert.NotNil(t, err, "Should return an error for duplicate leaf names")
+}
+
+func TestPrune(t *testing.T) {
+	// TEST1: prune a leaf.
+	pruned, names, err := Prune(subtreeDendrogram, subtreeNames, []string{"d"})
+	want := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 3, Lengtha: 1, Lengthb: 1, Node: 4, Height: 2, Size: 2},
+		{Leafa: 4, Leafb: 1, Lengtha: 2.95, Lengthb: 3.95, Node: 5, Height: 7.9, Size: 3},
+		{Leafa: 5, Leafb: 2, Lengtha: 4.71, Lengthb: 8.66, Node: 6, Height: 17.32, Size: 4},
+	}
+	assert.Nil(t, err, "Should not return an error for valid names")
+	assert.Equal(t, []string{"a", "b", "c", "e"}, names, "Should return names of remaining leafs")
+	for i, cluster := range pruned {
+		assert.Equal(t, want[i].Leafa, cluster.Leafa, "Should renumber left child")
+		assert.Equal(t, want[i].Leafb, cluster.Leafb, "Should renumber right child")
+		assert.InDelta(t, want[i].Lengtha, cluster.Lengtha, 0.0001, "Should adjust left branch length")
+		assert.InDelta(t, want[i].Lengthb, cluster.Lengthb, 0.0001, "Should adjust right branch length")
+		assert.Equal(t, want[i].Node, cluster.Node, "Should renumber node")
+		assert.Equal(t, want[i].Size, cluster.Size, "Should recalculate size")
+	}
+
+	// TEST2: pruned dendrogram has the same leaf order.
+	assert.Equal(t, []int{0, 3, 1, 2}, LeafOrder(pruned), "Should keep leaf order")
+	prunedTree, err := tree.Create(pruned, names)
+	assert.Nil(t, err, "Pruned dendrogram should be a valid input for creating a tree")
+	assert.Equal(t, []string{"a", "e", "b", "c"}, prunedTree.Order, "Should create tree from pruned dendrogram")
+
+	// TEST3: names do not match dendrogram.
+	_, _, err = Prune(subtreeDendrogram, subtreeNames[:4], []string{"a"})
+	assert.NotNil(t, err, "Should return an error when names do not match leafs")
+
+	// TEST4: prune every leaf.
+	_, _, err = Prune(subtreeDendrogram, subtreeNames, subtreeNames)
+	assert.NotNil(t, err, "Should return an error when every leaf is pruned")
+}
@@ -1,13 +1,27 @@
 // Package distance contains methods to generate a distance matrix.
 package distance
 
-import "github.com/knightjdr/hclust/matrixop"
+import (
+	"context"
+
+	"github.com/knightjdr/hclust/matrixop"
+	"github.com/knightjdr/hclust/typedef"
+)
 
 // Distance generates a square matrix of distance values calculated between row
 // vectors of an input matrix. Setting tranpose to true will calculate the distance matrix for
 // column vectors instead. Distance metric options are: binary, canberra, euclidean, jaccard,
 // manhattan and maximum.
 func Distance(matrix [][]float64, metric string, transpose bool) (dist [][]float64) {
+	dist, _ = DistanceContext(context.Background(), matrix, metric, transpose, nil)
+	return
+}
+
+// DistanceContext is the same as Distance but stops with the context's error if
+// it is cancelled. Cancellation is checked after each row. If progress is not nil
+// it is called after each row with the number of rows completed and the total
+// number of rows.
+func DistanceContext(ctx context.Context, matrix [][]float64, metric string, transpose bool, progress typedef.Progress) (dist [][]float64, err error) {
 	// Get distance function.
 	distMetric := DistMetric(metric)
 
@@ -31,6 +45,13 @@ func Distance(matrix [][]float64, metric string, transpose bool) (dist [][]float
 			dist[i][j] = elementDist
 			dist[j][i] = elementDist
 		}
+
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		if progress != nil {
+			progress(i+1, dim)
+		}
 	}
 
 	return
@@ -1,6 +1,7 @@
 package distance
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -38,3 +39,29 @@ func TestDistance(t *testing.T) {
 		assert.InDeltaSlice(t, want[i], row, 0.01, "Distance of transposed matrix not correct")
 	}
 }
+
+func TestDistanceContext(t *testing.T) {
+	matrix := [][]float64{
+		{5, 2, 14.3, 2.1},
+		{23, 17.8, 0, 0.4},
+		{10, 0, 7, 15.9},
+	}
+
+	// TEST1: progress is reported after every row.
+	completed := make([]int, 0)
+	progress := func(done, total int) {
+		assert.Equal(t, 3, total, "Total rows not correct")
+		completed = append(completed, done)
+	}
+	dist, err := DistanceContext(context.Background(), matrix, "maximum", false, progress)
+	assert.Nil(t, err, "Distance should not return an error")
+	assert.Equal(t, Distance(matrix, "maximum", false), dist, "Distance matrix should match Distance")
+	assert.Equal(t, []int{1, 2, 3}, completed, "Progress not reported correctly")
+
+	// TEST2: cancelled context stops calculation.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	dist, err = DistanceContext(ctx, matrix, "maximum", false, nil)
+	assert.Equal(t, context.Canceled, err, "Cancelled context should return error")
+	assert.Nil(t, dist, "Cancelled context should not return a distance matrix")
+}
@@ -0,0 +1,25 @@
+package distance
+
+import "github.com/knightjdr/hclust/matrixop"
+
+// Lazy returns a function that calculates the distance between two row vectors
+// of an input matrix on demand, for use when a full distance matrix will not fit
+// in memory. Setting transpose to true will calculate distances between column
+// vectors instead. Metric options are the same as for Distance.
+func Lazy(matrix [][]float64, metric string, transpose bool) func(i, j int) float64 {
+	// Get distance function.
+	distMetric := DistMetric(metric)
+
+	// Transpose matrix if requested.
+	if transpose {
+		matrix = matrixop.Transpose(matrix)
+	}
+
+	return func(i, j int) float64 {
+		if i == j {
+			return 0
+		}
+		dist, _ := distMetric(matrix[i], matrix[j])
+		return dist
+	}
+}
@@ -0,0 +1,33 @@
+package distance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazy(t *testing.T) {
+	matrix := [][]float64{
+		{5, 2, 14.3, 2.1},
+		{23, 17.8, 0, 0.4},
+		{10, 0, 7, 15.9},
+	}
+
+	// TEST1: lazy distances between rows should match the distance matrix.
+	want := Distance(matrix, "maximum", false)
+	dist := Lazy(matrix, "maximum", false)
+	for i := range want {
+		for j := range want[i] {
+			assert.InDelta(t, want[i][j], dist(i, j), 0.000001, "Lazy distance not correct")
+		}
+	}
+
+	// TEST2: lazy distances between columns should match the distance matrix.
+	want = Distance(matrix, "maximum", true)
+	dist = Lazy(matrix, "maximum", true)
+	for i := range want {
+		for j := range want[i] {
+			assert.InDelta(t, want[i][j], dist(i, j), 0.000001, "Lazy distance of transposed matrix not correct")
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Package export has methods for writing dendrograms in formats used by tree
+// and heatmap viewers, and for reading them back from JSON.
+package export
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// Properties are optional values written for each node of a tree.
+type Properties struct {
+	// Support values (e.g. bootstrap support) for internal nodes, in dendrogram
+	// order.
+	Support []float64
+
+	// Labels are the cluster labels for each leaf, such as from a cut of the
+	// dendrogram.
+	Labels []int
+}
+
+func (properties Properties) validate(dendrogram typedef.Dendrogram) (err error) {
+	if properties.Support != nil && len(properties.Support) != len(dendrogram) {
+		return errors.New("There must be one support value for each internal node")
+	}
+	if properties.Labels != nil && len(properties.Labels) != dendrogram.NumLeafs() {
+		return errors.New("There must be one cluster label for each leaf")
+	}
+	return
+}
+
+func validateNames(dendrogram typedef.Dendrogram, names []string) (err error) {
+	if len(names) != dendrogram.NumLeafs() {
+		return errors.New("The names vector must have the same dimension as the leaf number")
+	}
+	return
+}
+
+// branch is a node visited while walking a tree. Child is 0 for a left child,
+// 1 for a right child and -1 for the top node. Index is the node's position in
+// the dendrogram, or -1 for a leaf.
+type branch struct {
+	child  int
+	index  int
+	length float64
+	node   int
+}
+
+// walk visits the nodes of a dendrogram depth first from the top node, left
+// branches first. Enter is called before a node's children and exit after
+// them.
+func walk(dendrogram typedef.Dendrogram, enter, exit func(b branch)) {
+	n := dendrogram.NumLeafs()
+	nodeIndex := make([]int, n-1)
+	for i, cluster := range dendrogram {
+		nodeIndex[cluster.Node-n] = i
+	}
+
+	type visit struct {
+		branch
+		expanded bool
+	}
+	top := branch{child: -1, index: -1, node: 2*n - 2}
+	if n > 1 {
+		top.index = nodeIndex[top.node-n]
+	}
+	stack := []visit{{branch: top}}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		if current.expanded {
+			exit(current.branch)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		enter(current.branch)
+		if current.index < 0 {
+			exit(current.branch)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		stack[len(stack)-1].expanded = true
+		cluster := dendrogram[current.index]
+		for child, node := range []int{cluster.Leafb, cluster.Leafa} {
+			b := branch{child: 1 - child, index: -1, node: node}
+			b.length = cluster.Lengthb
+			if child == 1 {
+				b.length = cluster.Lengtha
+			}
+			if node >= n {
+				b.index = nodeIndex[node-n]
+			}
+			stack = append(stack, visit{branch: b})
+		}
+	}
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
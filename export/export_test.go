@@ -0,0 +1,45 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+var testDendrogram = typedef.Dendrogram{
+	{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5, Height: 2, Size: 2},
+	{Leafa: 5, Leafb: 1, Lengtha: 2.95, Lengthb: 3.95, Node: 6, Height: 7.9, Size: 3},
+	{Leafa: 3, Leafb: 2, Lengtha: 6.1, Lengthb: 6.1, Node: 7, Height: 12.2, Size: 2},
+	{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.56, Node: 8, Height: 17.32, Size: 5},
+}
+
+func TestWalk(t *testing.T) {
+	// TEST1: visit nodes depth first, left branches first.
+	entered := make([]int, 0)
+	exited := make([]int, 0)
+	children := make([]int, 0)
+	enter := func(b branch) {
+		entered = append(entered, b.node)
+		children = append(children, b.child)
+	}
+	exit := func(b branch) {
+		exited = append(exited, b.node)
+	}
+	walk(testDendrogram, enter, exit)
+	assert.Equal(t, []int{8, 6, 5, 0, 4, 1, 7, 3, 2}, entered, "Should enter nodes in pre-order")
+	assert.Equal(t, []int{0, 4, 5, 1, 6, 3, 2, 7, 8}, exited, "Should exit nodes in post-order")
+	assert.Equal(t, []int{-1, 0, 0, 0, 1, 1, 1, 0, 1}, children, "Should report whether each node is a left or right child")
+}
+
+func TestProperties(t *testing.T) {
+	// TEST1: valid properties.
+	properties := Properties{Support: []float64{1, 2, 3, 4}, Labels: []int{1, 1, 2, 2, 1}}
+	assert.Nil(t, properties.validate(testDendrogram), "Should not return an error for valid properties")
+
+	// TEST2: invalid properties.
+	properties = Properties{Support: []float64{1}}
+	assert.NotNil(t, properties.validate(testDendrogram), "Should return an error for missing support values")
+	properties = Properties{Labels: []int{1}}
+	assert.NotNil(t, properties.validate(testDendrogram), "Should return an error for missing cluster labels")
+}
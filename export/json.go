@@ -0,0 +1,267 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// JSON writes a dendrogram to w as a nested JSON hierarchy, as read by
+// d3.hierarchy. Each node is an object with its merge height (0 for leafs)
+// and, for internal nodes, its left and right children:
+//
+//	{"height": 7.9, "children": [{"name": "a", "height": 0}, ...]}
+//
+// Leafs also have their name and, when supplied, their cluster label
+// ("cluster") and attributes ("attributes"). Attributes are optional (nil) but
+// if supplied there must be one entry per leaf; nil entries are not written.
+// Internal nodes have a "support" value when supplied in properties. The tree
+// is written iteratively, so the depth of the tree is not limited.
+func JSON(w io.Writer, dendrogram typedef.Dendrogram, names []string, properties Properties, attributes []map[string]interface{}) (err error) {
+	if err = validateNames(dendrogram, names); err != nil {
+		return
+	}
+	if err = properties.validate(dendrogram); err != nil {
+		return
+	}
+	if attributes != nil && len(attributes) != len(names) {
+		return errors.New("There must be one set of attributes for each leaf")
+	}
+
+	// Encode names and attributes first so encoding errors are returned before
+	// anything is written.
+	encodedNames := make([]string, len(names))
+	for i, name := range names {
+		encoded, _ := json.Marshal(name)
+		encodedNames[i] = string(encoded)
+	}
+	encodedAttributes := make([]string, len(attributes))
+	for i, leafAttributes := range attributes {
+		if leafAttributes == nil {
+			continue
+		}
+		encoded, marshalErr := json.Marshal(leafAttributes)
+		if marshalErr != nil {
+			return marshalErr
+		}
+		encodedAttributes[i] = string(encoded)
+	}
+
+	heights := dendrogram.Heights()
+	buffer := bufio.NewWriter(w)
+	enter := func(b branch) {
+		if b.child == 1 {
+			buffer.WriteByte(',')
+		}
+		if b.index < 0 {
+			buffer.WriteString(`{"name":` + encodedNames[b.node] + `,"height":0`)
+			if properties.Labels != nil {
+				buffer.WriteString(`,"cluster":` + strconv.Itoa(properties.Labels[b.node]))
+			}
+			if attributes != nil && encodedAttributes[b.node] != "" {
+				buffer.WriteString(`,"attributes":` + encodedAttributes[b.node])
+			}
+			return
+		}
+		buffer.WriteString(`{"height":` + formatFloat(heights[b.index]))
+		if properties.Support != nil {
+			buffer.WriteString(`,"support":` + formatFloat(properties.Support[b.index]))
+		}
+		buffer.WriteString(`,"children":[`)
+	}
+	exit := func(b branch) {
+		if b.index >= 0 {
+			buffer.WriteByte(']')
+		}
+		buffer.WriteByte('}')
+	}
+	walk(dendrogram, enter, exit)
+	buffer.WriteString("\n")
+
+	return buffer.Flush()
+}
+
+// jsonNode is a node read from a JSON hierarchy.
+type jsonNode struct {
+	attributes map[string]interface{}
+	children   []int
+	cluster    *int
+	height     float64
+	name       *string
+	support    *float64
+}
+
+// ParseJSON reads a nested JSON hierarchy, as written by JSON, and returns the
+// dendrogram, leaf names, properties and leaf attributes. Leafs are numbered in
+// the order they appear and nodes are numbered as for cluster.Cluster, in
+// order of merge height. Branch lengths are half the difference between the
+// heights of a node and its child, with leafs at a height of 0. Every leaf
+// must have a name and every internal node two children. Properties.Labels is
+// set if any leaf has a cluster label, Properties.Support if any internal node
+// has a support value and attributes if any leaf has attributes, with missing
+// values set to 0 (or nil for attributes). Unknown keys are ignored. The JSON
+// decoder limits nesting to 10000 objects and arrays, so trees with more than
+// 4999 internal nodes on a path from the root cannot be read and the error
+// includes the decoder's reason.
+func ParseJSON(r io.Reader) (dendrogram typedef.Dendrogram, names []string, properties Properties, attributes []map[string]interface{}, err error) {
+	nodes, err := parseJSONNodes(r)
+	if err != nil {
+		return
+	}
+
+	// Number leafs in order of appearance.
+	id := make([]int, len(nodes))
+	hasAttributes, hasLabels, hasSupport := false, false, false
+	for i, node := range nodes {
+		if len(node.children) == 0 {
+			if node.name == nil {
+				err = errors.New("Leafs must be named")
+				return
+			}
+			id[i] = len(names)
+			names = append(names, *node.name)
+			hasAttributes = hasAttributes || node.attributes != nil
+			hasLabels = hasLabels || node.cluster != nil
+		} else if len(node.children) != 2 {
+			err = errors.New("Internal nodes must have two children")
+			return
+		} else {
+			hasSupport = hasSupport || node.support != nil
+		}
+	}
+	n := len(names)
+	if hasAttributes {
+		attributes = make([]map[string]interface{}, n)
+	}
+	if hasLabels {
+		properties.Labels = make([]int, n)
+	}
+	for i, node := range nodes {
+		if len(node.children) > 0 {
+			continue
+		}
+		if hasAttributes {
+			attributes[id[i]] = node.attributes
+		}
+		if node.cluster != nil {
+			properties.Labels[id[i]] = *node.cluster
+		}
+	}
+
+	// Children always follow their parent in the nodes slice, so visiting nodes
+	// in reverse creates merges for children before their parents. Merges are
+	// then ordered by height and numbered from n.
+	merges := make([][2]int, 0, n-1)
+	heights := make([]float64, 0, n-1)
+	mergeNode := make([]int, 0, n-1)
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if len(nodes[i].children) > 0 {
+			id[i] = n + len(merges)
+			merges = append(merges, [2]int{id[nodes[i].children[0]], id[nodes[i].children[1]]})
+			heights = append(heights, nodes[i].height)
+			mergeNode = append(mergeNode, i)
+		}
+	}
+	dendrogram, order, err := typedef.FromMerges(merges, heights, nil, true)
+	if err != nil {
+		return
+	}
+	if hasSupport {
+		properties.Support = make([]float64, len(merges))
+		for i, index := range order {
+			if support := nodes[mergeNode[index]].support; support != nil {
+				properties.Support[i] = *support
+			}
+		}
+	}
+	return
+}
+
+// parseJSONNodes reads the nodes of a JSON hierarchy in pre-order, with
+// children listed left to right.
+func parseJSONNodes(r io.Reader) (nodes []jsonNode, err error) {
+	invalid := errors.New("Invalid JSON hierarchy")
+	decodeError := func(err error) error {
+		return fmt.Errorf("Invalid JSON hierarchy: %v", err)
+	}
+	decoder := json.NewDecoder(r)
+	if token, tokenErr := decoder.Token(); tokenErr != nil {
+		return nil, decodeError(tokenErr)
+	} else if token != json.Delim('{') {
+		return nil, invalid
+	}
+
+	type frame struct {
+		inChildren bool
+		node       int
+	}
+	nodes = []jsonNode{{}}
+	stack := []frame{{node: 0}}
+	for len(stack) > 0 {
+		top := len(stack) - 1
+		token, tokenErr := decoder.Token()
+		if tokenErr != nil {
+			return nil, decodeError(tokenErr)
+		}
+
+		if stack[top].inChildren {
+			if token == json.Delim(']') {
+				stack[top].inChildren = false
+			} else if token == json.Delim('{') {
+				nodes = append(nodes, jsonNode{})
+				child := len(nodes) - 1
+				parent := stack[top].node
+				nodes[parent].children = append(nodes[parent].children, child)
+				stack = append(stack, frame{node: child})
+			} else {
+				return nil, invalid
+			}
+			continue
+		}
+
+		if token == json.Delim('}') {
+			stack = stack[:top]
+			continue
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, invalid
+		}
+		node := &nodes[stack[top].node]
+		switch key {
+		case "children":
+			if token, tokenErr = decoder.Token(); tokenErr != nil {
+				return nil, decodeError(tokenErr)
+			} else if token != json.Delim('[') {
+				return nil, invalid
+			}
+			stack[top].inChildren = true
+		case "name":
+			err = decoder.Decode(&node.name)
+		case "height":
+			err = decoder.Decode(&node.height)
+		case "cluster":
+			err = decoder.Decode(&node.cluster)
+		case "support":
+			err = decoder.Decode(&node.support)
+		case "attributes":
+			err = decoder.Decode(&node.attributes)
+		default:
+			var skip json.RawMessage
+			err = decoder.Decode(&skip)
+		}
+		if err != nil {
+			return nil, decodeError(err)
+		}
+	}
+
+	if _, tokenErr := decoder.Token(); tokenErr != io.EOF {
+		return nil, errors.New("Unexpected data after JSON hierarchy")
+	}
+	return
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSON(t *testing.T) {
+	names := []string{"a", "b", "c", "d\"", "e"}
+
+	// TEST1: write a hierarchy without optional values.
+	var buffer bytes.Buffer
+	err := JSON(&buffer, testDendrogram, names, Properties{}, nil)
+	want := `{"height":17.32,"children":[` +
+		`{"height":7.9,"children":[{"height":2,"children":[{"name":"a","height":0},{"name":"e","height":0}]},{"name":"b","height":0}]},` +
+		`{"height":12.2,"children":[{"name":"d\"","height":0},{"name":"c","height":0}]}]}` + "\n"
+	assert.Nil(t, err, "Should not return an error for a valid tree")
+	assert.Equal(t, want, buffer.String(), "Should write nested hierarchy")
+
+	// TEST2: write a hierarchy with properties and attributes.
+	buffer.Reset()
+	properties := Properties{Support: []float64{90, 80, 70, 100}, Labels: []int{1, 1, 2, 2, 1}}
+	attributes := []map[string]interface{}{{"group": "x", "score": 1.5}, nil, nil, nil, nil}
+	err = JSON(&buffer, testDendrogram, names, properties, attributes)
+	want = `{"height":17.32,"support":100,"children":[` +
+		`{"height":7.9,"support":80,"children":[{"height":2,"support":90,"children":[{"name":"a","height":0,"cluster":1,"attributes":{"group":"x","score":1.5}},{"name":"e","height":0,"cluster":1}]},{"name":"b","height":0,"cluster":1}]},` +
+		`{"height":12.2,"support":70,"children":[{"name":"d\"","height":0,"cluster":2},{"name":"c","height":0,"cluster":2}]}]}` + "\n"
+	assert.Nil(t, err, "Should not return an error with optional values")
+	assert.Equal(t, want, buffer.String(), "Should write hierarchy with optional values")
+
+	// TEST3: invalid input.
+	err = JSON(&buffer, testDendrogram, names[:2], Properties{}, nil)
+	assert.NotNil(t, err, "Should return an error when names do not match leafs")
+	err = JSON(&buffer, testDendrogram, names, Properties{}, attributes[:1])
+	assert.NotNil(t, err, "Should return an error when attributes do not match leafs")
+	err = JSON(&buffer, testDendrogram, names, Properties{}, []map[string]interface{}{{"f": func() {}}, nil, nil, nil, nil})
+	assert.NotNil(t, err, "Should return an error when attributes cannot be encoded")
+}
+
+func TestParseJSON(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e"}
+
+	// TEST1: read a hierarchy written by JSON.
+	var buffer bytes.Buffer
+	properties := Properties{Support: []float64{90, 80, 70, 100}, Labels: []int{1, 1, 2, 2, 1}}
+	attributes := []map[string]interface{}{{"group": "x"}, nil, nil, nil, nil}
+	JSON(&buffer, testDendrogram, names, properties, attributes)
+	dendrogram, parsedNames, parsedProperties, parsedAttributes, err := ParseJSON(&buffer)
+	wantDendrogram := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 1, Lengtha: 1, Lengthb: 1, Node: 5, Height: 2, Size: 2},
+		{Leafa: 5, Leafb: 2, Lengtha: 2.95, Lengthb: 3.95, Node: 6, Height: 7.9, Size: 3},
+		{Leafa: 3, Leafb: 4, Lengtha: 6.1, Lengthb: 6.1, Node: 7, Height: 12.2, Size: 2},
+		{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.5600000000000005, Node: 8, Height: 17.32, Size: 5},
+	}
+	assert.Nil(t, err, "Should not return an error for a valid hierarchy")
+	assert.Equal(t, wantDendrogram, dendrogram, "Should read dendrogram")
+	assert.Equal(t, []string{"a", "e", "b", "d", "c"}, parsedNames, "Should number leafs in order of appearance")
+	assert.Equal(t, []int{1, 1, 1, 2, 2}, parsedProperties.Labels, "Should read cluster labels")
+	assert.Equal(t, []float64{90, 80, 70, 100}, parsedProperties.Support, "Should read support values")
+	assert.Equal(t, []map[string]interface{}{{"group": "x"}, nil, nil, nil, nil}, parsedAttributes, "Should read attributes")
+
+	// TEST2: optional values and unknown keys.
+	hierarchy := `{"height": 1, "id": {"x": [1]}, "children": [{"name": "a"}, {"name": "b", "cluster": 3}]}`
+	dendrogram, parsedNames, parsedProperties, parsedAttributes, err = ParseJSON(strings.NewReader(hierarchy))
+	assert.Nil(t, err, "Should not return an error for a hierarchy with missing values")
+	assert.Equal(t, typedef.Dendrogram{{Leafa: 0, Leafb: 1, Lengtha: 0.5, Lengthb: 0.5, Node: 2, Height: 1, Size: 2}}, dendrogram, "Should read dendrogram with missing values")
+	assert.Equal(t, []string{"a", "b"}, parsedNames, "Should read names with missing values")
+	assert.Equal(t, []int{0, 3}, parsedProperties.Labels, "Should set missing cluster labels to 0")
+	assert.Nil(t, parsedProperties.Support, "Should not set support values when none are present")
+	assert.Nil(t, parsedAttributes, "Should not set attributes when none are present")
+
+	// TEST3: nodes are numbered by height.
+	hierarchy = `{"height": 3, "children": [{"height": 2, "children": [{"name": "a"}, {"name": "b"}]}, {"height": 1, "children": [{"name": "c"}, {"name": "d"}]}]}`
+	dendrogram, _, _, _, _ = ParseJSON(strings.NewReader(hierarchy))
+	wantDendrogram = typedef.Dendrogram{
+		{Leafa: 2, Leafb: 3, Lengtha: 0.5, Lengthb: 0.5, Node: 4, Height: 1, Size: 2},
+		{Leafa: 0, Leafb: 1, Lengtha: 1, Lengthb: 1, Node: 5, Height: 2, Size: 2},
+		{Leafa: 5, Leafb: 4, Lengtha: 0.5, Lengthb: 1, Node: 6, Height: 3, Size: 4},
+	}
+	assert.Equal(t, wantDendrogram, dendrogram, "Should number nodes by merge height")
+
+	// TEST4: deep hierarchy.
+	depth := 4999
+	deep := strings.Repeat(`{"height":1,"children":[{"name":"x"},`, depth) + `{"name":"y"}` + strings.Repeat("]}", depth)
+	dendrogram, parsedNames, _, _, err = ParseJSON(strings.NewReader(deep))
+	assert.Nil(t, err, "Should read a deep hierarchy")
+	assert.Len(t, dendrogram, depth, "Should read every node of a deep hierarchy")
+	assert.Len(t, parsedNames, depth+1, "Should read every leaf of a deep hierarchy")
+	tooDeep := strings.Repeat(`{"height":1,"children":[{"name":"x"},`, 6000) + `{"name":"y"}` + strings.Repeat("]}", 6000)
+	_, _, _, _, err = ParseJSON(strings.NewReader(tooDeep))
+	assert.Contains(t, err.Error(), "exceeded max depth", "Should report a hierarchy that is too deep")
+
+	// TEST5: invalid hierarchies.
+	invalid := []string{
+		`[]`,
+		`{"height": 1, "children": [{"name": "a"}]}`,
+		`{"height": 1, "children": [{"name": "a"}, {"height": 0}]}`,
+		`{"height": 1, "children": [{"name": "a"}, {"name": 2}]}`,
+		`{"height": 1, "children": [{"name": "a"}, {"name": "b"}]`,
+		`{"name": "a"} {"name": "b"}`,
+	}
+	for _, hierarchy := range invalid {
+		_, _, _, _, err = ParseJSON(strings.NewReader(hierarchy))
+		assert.NotNil(t, err, "Should return an error for invalid hierarchy: "+hierarchy)
+	}
+}
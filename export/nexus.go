@@ -0,0 +1,90 @@
+package export
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// nexusPunctuation are the characters that require a Nexus token to be quoted.
+// An unquoted underscore is read as a space, so it is quoted too.
+const nexusPunctuation = " \t\r\n()[]{}/\\,;:=*'\"`+-<>_"
+
+// quoteNexus quotes a name if it contains whitespace, Nexus punctuation or
+// underscores, doubling any single quotes.
+func quoteNexus(name string) string {
+	if name != "" && !strings.ContainsAny(name, nexusPunctuation) {
+		return name
+	}
+	return "'" + strings.Replace(name, "'", "''", -1) + "'"
+}
+
+// Nexus writes a dendrogram and its leaf names to w in Nexus format, with a
+// TAXA block and a TREES block. Leafs are referenced in the tree by their
+// number in the TRANSLATE command (the leaf index plus 1). The merge height,
+// size and support of internal nodes and the cluster labels of leafs are
+// written as [&key=value] comments, as read by FigTree.
+func Nexus(w io.Writer, dendrogram typedef.Dendrogram, names []string, properties Properties) (err error) {
+	if err = validateNames(dendrogram, names); err != nil {
+		return
+	}
+	if err = properties.validate(dendrogram); err != nil {
+		return
+	}
+
+	buffer := bufio.NewWriter(w)
+	buffer.WriteString("#NEXUS\n\nBEGIN TAXA;\n")
+	buffer.WriteString("\tDIMENSIONS NTAX=" + strconv.Itoa(len(names)) + ";\n")
+	buffer.WriteString("\tTAXLABELS\n")
+	for _, name := range names {
+		buffer.WriteString("\t\t" + quoteNexus(name) + "\n")
+	}
+	buffer.WriteString("\t;\nEND;\n\nBEGIN TREES;\n\tTRANSLATE\n")
+	for i, name := range names {
+		separator := ","
+		if i == len(names)-1 {
+			separator = ""
+		}
+		buffer.WriteString("\t\t" + strconv.Itoa(i+1) + " " + quoteNexus(name) + separator + "\n")
+	}
+	buffer.WriteString("\t;\n\tTREE hclust = [&R] ")
+
+	heights := dendrogram.Heights()
+	sizes := dendrogram.Sizes()
+	enter := func(b branch) {
+		if b.child == 1 {
+			buffer.WriteByte(',')
+		}
+		if b.index >= 0 {
+			buffer.WriteByte('(')
+		}
+	}
+	exit := func(b branch) {
+		annotations := make([]string, 0, 3)
+		if b.index < 0 {
+			buffer.WriteString(strconv.Itoa(b.node + 1))
+			if properties.Labels != nil {
+				annotations = append(annotations, "cluster="+strconv.Itoa(properties.Labels[b.node]))
+			}
+		} else {
+			buffer.WriteByte(')')
+			annotations = append(annotations, "height="+formatFloat(heights[b.index]), "size="+strconv.Itoa(sizes[b.index]))
+			if properties.Support != nil {
+				annotations = append(annotations, "support="+formatFloat(properties.Support[b.index]))
+			}
+		}
+		if len(annotations) > 0 {
+			buffer.WriteString("[&" + strings.Join(annotations, ",") + "]")
+		}
+		if b.child >= 0 {
+			buffer.WriteString(":" + formatFloat(b.length))
+		}
+	}
+	walk(dendrogram, enter, exit)
+	buffer.WriteString(";\nEND;\n")
+
+	return buffer.Flush()
+}
@@ -0,0 +1,45 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteNexus(t *testing.T) {
+	assert.Equal(t, "leaf1", quoteNexus("leaf1"), "Should not quote plain name")
+	assert.Equal(t, "'leaf_1'", quoteNexus("leaf_1"), "Should quote name with an underscore")
+	assert.Equal(t, "'gene-1'", quoteNexus("gene-1"), "Should quote name with punctuation")
+	assert.Equal(t, "'it''s'", quoteNexus("it's"), "Should double single quotes")
+}
+
+func TestNexus(t *testing.T) {
+	names := []string{"a", "b", "c", "d d", "e"}
+
+	// TEST1: write a tree with properties.
+	var buffer bytes.Buffer
+	properties := Properties{Support: []float64{90, 80, 70, 100}, Labels: []int{1, 1, 2, 2, 1}}
+	err := Nexus(&buffer, testDendrogram, names, properties)
+	want := "#NEXUS\n\n" +
+		"BEGIN TAXA;\n" +
+		"\tDIMENSIONS NTAX=5;\n" +
+		"\tTAXLABELS\n\t\ta\n\t\tb\n\t\tc\n\t\t'd d'\n\t\te\n\t;\n" +
+		"END;\n\n" +
+		"BEGIN TREES;\n" +
+		"\tTRANSLATE\n\t\t1 a,\n\t\t2 b,\n\t\t3 c,\n\t\t4 'd d',\n\t\t5 e\n\t;\n" +
+		"\tTREE hclust = [&R] (((1[&cluster=1]:1,5[&cluster=1]:1)[&height=2,size=2,support=90]:2.95,2[&cluster=1]:3.95)[&height=7.9,size=3,support=80]:4.71," +
+		"(4[&cluster=2]:6.1,3[&cluster=2]:6.1)[&height=12.2,size=2,support=70]:2.56)[&height=17.32,size=5,support=100];\n" +
+		"END;\n"
+	assert.Nil(t, err, "Should not return an error for a valid tree")
+	assert.Equal(t, want, buffer.String(), "Should write nexus file")
+
+	// TEST2: write a tree without optional properties.
+	buffer.Reset()
+	Nexus(&buffer, testDendrogram, names, Properties{})
+	assert.Contains(t, buffer.String(), "\tTREE hclust = [&R] (((1:1,5:1)[&height=2,size=2]:2.95,2:3.95)[&height=7.9,size=3]:4.71,(4:6.1,3:6.1)[&height=12.2,size=2]:2.56)[&height=17.32,size=5];\n", "Should write tree without optional properties")
+
+	// TEST3: names of incorrect length.
+	err = Nexus(&buffer, testDendrogram, names[:2], Properties{})
+	assert.NotNil(t, err, "Should return an error when names do not match leafs")
+}
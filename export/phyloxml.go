@@ -0,0 +1,71 @@
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+const phyloXMLHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<phyloxml xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.phyloxml.org http://www.phyloxml.org/1.10/phyloxml.xsd" xmlns="http://www.phyloxml.org">
+<phylogeny rooted="true">
+`
+
+const phyloXMLFooter = `</phylogeny>
+</phyloxml>
+`
+
+// PhyloXML writes a dendrogram and its leaf names to w in PhyloXML format.
+// Branch lengths are those of the dendrogram. The merge height and size of each
+// node are written as clade properties (hclust:height and hclust:size), support
+// values as bootstrap confidences and cluster labels as leaf properties
+// (hclust:cluster).
+func PhyloXML(w io.Writer, dendrogram typedef.Dendrogram, names []string, properties Properties) (err error) {
+	if err = validateNames(dendrogram, names); err != nil {
+		return
+	}
+	if err = properties.validate(dendrogram); err != nil {
+		return
+	}
+
+	heights := dendrogram.Heights()
+	sizes := dendrogram.Sizes()
+	buffer := bufio.NewWriter(w)
+	writeProperty := func(ref, datatype, value string) {
+		buffer.WriteString(`<property ref="hclust:` + ref + `" datatype="xsd:` + datatype + `" applies_to="clade">` + value + "</property>\n")
+	}
+
+	buffer.WriteString(phyloXMLHeader)
+	enter := func(b branch) {
+		buffer.WriteString("<clade>\n")
+		if b.index < 0 {
+			buffer.WriteString("<name>")
+			xml.EscapeText(buffer, []byte(names[b.node]))
+			buffer.WriteString("</name>\n")
+		}
+		if b.child >= 0 {
+			buffer.WriteString("<branch_length>" + formatFloat(b.length) + "</branch_length>\n")
+		}
+		if b.index < 0 {
+			if properties.Labels != nil {
+				writeProperty("cluster", "integer", strconv.Itoa(properties.Labels[b.node]))
+			}
+			return
+		}
+		if properties.Support != nil {
+			buffer.WriteString(`<confidence type="bootstrap">` + formatFloat(properties.Support[b.index]) + "</confidence>\n")
+		}
+		writeProperty("height", "double", formatFloat(heights[b.index]))
+		writeProperty("size", "integer", strconv.Itoa(sizes[b.index]))
+	}
+	exit := func(b branch) {
+		buffer.WriteString("</clade>\n")
+	}
+	walk(dendrogram, enter, exit)
+	buffer.WriteString(phyloXMLFooter)
+
+	return buffer.Flush()
+}
@@ -0,0 +1,186 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testClade struct {
+	BranchLength string      `xml:"branch_length"`
+	Clades       []testClade `xml:"clade"`
+	Confidence   []struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"confidence"`
+	Name       string `xml:"name"`
+	Properties []struct {
+		AppliesTo string `xml:"applies_to,attr"`
+		Datatype  string `xml:"datatype,attr"`
+		Ref       string `xml:"ref,attr"`
+		Value     string `xml:",chardata"`
+	} `xml:"property"`
+}
+
+type testPhyloXML struct {
+	XMLName   xml.Name `xml:"http://www.phyloxml.org phyloxml"`
+	Phylogeny struct {
+		Rooted string    `xml:"rooted,attr"`
+		Clade  testClade `xml:"clade"`
+	} `xml:"phylogeny"`
+}
+
+// cladeElementOrder is the sequence of clade child elements in the PhyloXML
+// 1.10 schema.
+var cladeElementOrder = []string{
+	"name", "branch_length", "confidence", "width", "color", "node_id", "taxonomy", "sequence",
+	"events", "binary_characters", "distribution", "date", "reference", "property", "clade",
+}
+
+// checkPhyloXML checks some of the rules of the PhyloXML 1.10 schema: the order
+// of clade child elements, the ref pattern and attributes of properties and
+// that branch lengths and confidences are numbers. It is a fallback for when
+// xmllint is not installed; TestPhyloXMLSchema validates against the schema.
+func checkPhyloXML(t *testing.T, document string) {
+	rank := make(map[string]int, len(cladeElementOrder))
+	for i, element := range cladeElementOrder {
+		rank[element] = i
+	}
+	refPattern := regexp.MustCompile(`^[a-zA-Z0-9_]+:[a-zA-Z0-9_]+$`)
+
+	decoder := xml.NewDecoder(strings.NewReader(document))
+	type open struct {
+		name string
+		last int
+	}
+	stack := make([]open, 0)
+	text := ""
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			name := element.Name.Local
+			if len(stack) > 0 && stack[len(stack)-1].name == "clade" {
+				position, ok := rank[name]
+				assert.True(t, ok, "Clade child element should be in the schema: "+name)
+				assert.GreaterOrEqual(t, position, stack[len(stack)-1].last, "Clade child elements should be in schema order: "+name)
+				if position == stack[len(stack)-1].last && position < rank["confidence"] {
+					t.Errorf("Clade element should not be repeated: %s", name)
+				}
+				stack[len(stack)-1].last = position
+			}
+			if name == "property" {
+				attrs := make(map[string]string, 0)
+				for _, attr := range element.Attr {
+					attrs[attr.Name.Local] = attr.Value
+				}
+				assert.Regexp(t, refPattern, attrs["ref"], "Property ref should match the schema pattern")
+				assert.True(t, strings.HasPrefix(attrs["datatype"], "xsd:"), "Property datatype should be an xsd type")
+				assert.Equal(t, "clade", attrs["applies_to"], "Property should apply to the clade")
+			}
+			stack = append(stack, open{name: name, last: -1})
+			text = ""
+		case xml.CharData:
+			text += string(element)
+		case xml.EndElement:
+			name := element.Name.Local
+			if name == "branch_length" || name == "confidence" {
+				_, err := strconv.ParseFloat(text, 64)
+				assert.Nil(t, err, "Element value should be a double: "+name)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	assert.Empty(t, stack, "Document should be well formed")
+}
+
+func TestPhyloXML(t *testing.T) {
+	names := []string{"a", "b", "c", "d<&>", "e"}
+	properties := Properties{Support: []float64{90, 80, 70, 100}, Labels: []int{1, 1, 2, 2, 1}}
+
+	// TEST1: write a document that follows the checked schema rules.
+	var buffer bytes.Buffer
+	err := PhyloXML(&buffer, testDendrogram, names, properties)
+	assert.Nil(t, err, "Should not return an error for a valid tree")
+	checkPhyloXML(t, buffer.String())
+
+	var document testPhyloXML
+	err = xml.Unmarshal(buffer.Bytes(), &document)
+	assert.Nil(t, err, "Should write valid XML")
+	assert.Equal(t, "true", document.Phylogeny.Rooted, "Should write rooted phylogeny")
+
+	// TEST2: tree structure and values.
+	root := document.Phylogeny.Clade
+	assert.Equal(t, "", root.BranchLength, "Should not write a branch length for the root")
+	assert.Equal(t, "100", root.Confidence[0].Value, "Should write root support")
+	assert.Equal(t, "bootstrap", root.Confidence[0].Type, "Should write support as bootstrap confidence")
+	assert.Equal(t, "hclust:height", root.Properties[0].Ref, "Should write height property")
+	assert.Equal(t, "17.32", root.Properties[0].Value, "Should write root height")
+	assert.Equal(t, "hclust:size", root.Properties[1].Ref, "Should write size property")
+	assert.Equal(t, "5", root.Properties[1].Value, "Should write root size")
+	assert.Len(t, root.Clades, 2, "Should write two children for the root")
+
+	right := root.Clades[1]
+	assert.Equal(t, "2.56", right.BranchLength, "Should write branch length")
+	assert.Equal(t, "70", right.Confidence[0].Value, "Should write node support")
+	leaf := right.Clades[0]
+	assert.Equal(t, "d<&>", leaf.Name, "Should escape leaf name")
+	assert.Equal(t, "6.1", leaf.BranchLength, "Should write leaf branch length")
+	assert.Equal(t, "hclust:cluster", leaf.Properties[0].Ref, "Should write cluster property")
+	assert.Equal(t, "2", leaf.Properties[0].Value, "Should write cluster label")
+	assert.Equal(t, "a", root.Clades[0].Clades[0].Clades[0].Name, "Should write leafs in dendrogram order")
+
+	// TEST3: write a document without optional properties.
+	buffer.Reset()
+	err = PhyloXML(&buffer, testDendrogram, names, Properties{})
+	assert.Nil(t, err, "Should not return an error without properties")
+	checkPhyloXML(t, buffer.String())
+	assert.NotContains(t, buffer.String(), "confidence", "Should not write support values")
+	assert.NotContains(t, buffer.String(), "hclust:cluster", "Should not write cluster labels")
+
+	// TEST4: invalid input.
+	err = PhyloXML(&buffer, testDendrogram, names[:3], Properties{})
+	assert.NotNil(t, err, "Should return an error when names do not match leafs")
+	err = PhyloXML(&buffer, testDendrogram, names, Properties{Support: []float64{1}})
+	assert.NotNil(t, err, "Should return an error when support values do not match nodes")
+}
+
+func TestPhyloXMLSchema(t *testing.T) {
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint is required to validate against the PhyloXML schema")
+	}
+	names := []string{"a", "b", "c", "d<&>", "e"}
+	propertySets := []Properties{
+		{Support: []float64{90, 80, 70, 100}, Labels: []int{1, 1, 2, 2, 1}},
+		{},
+	}
+
+	// TEST1: documents are valid against the PhyloXML 1.10 schema.
+	for i, properties := range propertySets {
+		var buffer bytes.Buffer
+		PhyloXML(&buffer, testDendrogram, names, properties)
+		file := filepath.Join(t.TempDir(), "tree"+strconv.Itoa(i)+".xml")
+		ioutil.WriteFile(file, buffer.Bytes(), 0644)
+		output, err := exec.Command(xmllint, "--noout", "--schema", filepath.Join("testdata", "phyloxml.xsd"), file).CombinedOutput()
+		assert.Nil(t, err, "Should write a document that is valid against the schema: "+string(output))
+	}
+
+	// TEST2: the schema rejects an invalid document.
+	file := filepath.Join(t.TempDir(), "invalid.xml")
+	invalid := strings.Replace(phyloXMLHeader, `rooted="true"`, `rooted="yes"`, 1) + "<clade><branch_length>x</branch_length></clade>\n" + phyloXMLFooter
+	ioutil.WriteFile(file, []byte(invalid), 0644)
+	_, err = exec.Command(xmllint, "--noout", "--schema", filepath.Join("testdata", "phyloxml.xsd"), file).CombinedOutput()
+	assert.NotNil(t, err, "Should reject a document that is not valid against the schema")
+}
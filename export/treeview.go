@@ -0,0 +1,214 @@
+package export
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/knightjdr/hclust/dendrogram"
+	"github.com/knightjdr/hclust/sort"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// TreeView writes a data matrix and its row and column dendrograms in the
+// Cluster 3.0 format read by Java TreeView: a CDT file with the reordered
+// data, a GTR file with the row tree and an ATR file with the column tree.
+// Rows and columns are ordered as for tree.Create and sort.Sort, so names must
+// be unique. Rows are identified in the files as GENE{i}X and columns as
+// ARRY{j}X, where i and j are their original indices, and the merge at
+// dendrogram index k as NODE{k+1}X. Merges are written with a similarity of
+// 1 minus the merge height, as Cluster 3.0 does for correlation distances.
+// When the highest merge is above 1 (e.g. for Euclidean distances) heights are
+// first divided by the highest merge so similarities stay between 0 and 1. A
+// nil dendrogram leaves the rows or columns in their original order and the
+// corresponding tree writer is not used (and may be nil). Missing values (NaN)
+// are written as empty cells.
+func TreeView(cdt, gtr, atr io.Writer, matrix [][]float64, rowNames, columnNames []string, rowDendrogram, columnDendrogram typedef.Dendrogram) (err error) {
+	if len(matrix) == 0 || len(matrix[0]) == 0 {
+		return errors.New("The data matrix must not be empty")
+	}
+	if cdt == nil || (rowDendrogram != nil && gtr == nil) || (columnDendrogram != nil && atr == nil) {
+		return errors.New("A writer is required for the data and each dendrogram")
+	}
+	for _, row := range matrix {
+		if len(row) != len(matrix[0]) {
+			return errors.New("All rows of the data matrix must have the same length")
+		}
+	}
+	rowOrder, err := treeViewOrder(rowDendrogram, rowNames, len(matrix))
+	if err != nil {
+		return
+	}
+	columnOrder, err := treeViewOrder(columnDendrogram, columnNames, len(matrix[0]))
+	if err != nil {
+		return
+	}
+
+	sorted, err := sort.Sort(matrix, rowNames, rowOrder.names, "row")
+	if err != nil {
+		return
+	}
+	if sorted, err = sort.Sort(sorted, columnNames, columnOrder.names, "column"); err != nil {
+		return
+	}
+
+	if rowDendrogram != nil {
+		if err = writeTreeViewTree(gtr, rowDendrogram, "GENE"); err != nil {
+			return
+		}
+	}
+	if columnDendrogram != nil {
+		if err = writeTreeViewTree(atr, columnDendrogram, "ARRY"); err != nil {
+			return
+		}
+	}
+
+	// Leading columns are GID (when rows are clustered), UNIQID, NAME and
+	// GWEIGHT.
+	leading := []string{"UNIQID", "NAME", "GWEIGHT"}
+	if rowDendrogram != nil {
+		leading = append([]string{"GID"}, leading...)
+	}
+	padding := strings.Repeat("\t", len(leading)-1)
+
+	buffer := bufio.NewWriter(cdt)
+	buffer.WriteString(strings.Join(leading, "\t"))
+	for _, name := range columnOrder.names {
+		buffer.WriteString("\t" + name)
+	}
+	buffer.WriteString("\n")
+	if columnDendrogram != nil {
+		buffer.WriteString("AID" + padding)
+		for _, column := range columnOrder.indices {
+			buffer.WriteString("\tARRY" + strconv.Itoa(column) + "X")
+		}
+		buffer.WriteString("\n")
+	}
+	buffer.WriteString("EWEIGHT" + padding)
+	for range columnOrder.indices {
+		buffer.WriteString("\t1")
+	}
+	buffer.WriteString("\n")
+	for i, row := range sorted {
+		if rowDendrogram != nil {
+			buffer.WriteString("GENE" + strconv.Itoa(rowOrder.indices[i]) + "X\t")
+		}
+		name := rowOrder.names[i]
+		buffer.WriteString(name + "\t" + name + "\t1")
+		for _, value := range row {
+			buffer.WriteString("\t")
+			if !math.IsNaN(value) {
+				buffer.WriteString(formatFloat(value))
+			}
+		}
+		buffer.WriteString("\n")
+	}
+	return buffer.Flush()
+}
+
+// TreeViewFiles writes the files for TreeView to prefix.cdt, prefix.gtr (when
+// there is a row dendrogram) and prefix.atr (when there is a column
+// dendrogram).
+func TreeViewFiles(prefix string, matrix [][]float64, rowNames, columnNames []string, rowDendrogram, columnDendrogram typedef.Dendrogram) (err error) {
+	files := make([]*os.File, 0, 3)
+	defer func() {
+		for _, file := range files {
+			if closeErr := file.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	}()
+	create := func(extension string, needed bool) (file *os.File, err error) {
+		if !needed {
+			return
+		}
+		if file, err = os.Create(prefix + extension); err == nil {
+			files = append(files, file)
+		}
+		return
+	}
+
+	cdt, err := create(".cdt", true)
+	if err != nil {
+		return
+	}
+	gtr, err := create(".gtr", rowDendrogram != nil)
+	if err != nil {
+		return
+	}
+	atr, err := create(".atr", columnDendrogram != nil)
+	if err != nil {
+		return
+	}
+	return TreeView(cdt, gtr, atr, matrix, rowNames, columnNames, rowDendrogram, columnDendrogram)
+}
+
+// leafOrder holds the original indices and names of rows or columns in the
+// order they are written.
+type leafOrder struct {
+	indices []int
+	names   []string
+}
+
+func treeViewOrder(dend typedef.Dendrogram, names []string, dimension int) (order leafOrder, err error) {
+	if len(names) != dimension {
+		err = errors.New("The names vector must have the same dimension as the data matrix")
+		return
+	}
+	unique := make(map[string]bool, len(names))
+	for _, name := range names {
+		if unique[name] {
+			err = errors.New("Names must be unique")
+			return
+		}
+		unique[name] = true
+	}
+
+	if dend == nil {
+		order.indices = make([]int, dimension)
+		for i := range order.indices {
+			order.indices[i] = i
+		}
+	} else {
+		if dend.NumLeafs() != dimension {
+			err = errors.New("The dendrogram must have one leaf for each row or column of the data matrix")
+			return
+		}
+		order.indices = dendrogram.LeafOrder(dend)
+	}
+	order.names = make([]string, dimension)
+	for i, index := range order.indices {
+		order.names[i] = names[index]
+	}
+	return
+}
+
+// writeTreeViewTree writes the merges of a dendrogram as a GTR or ATR file.
+func writeTreeViewTree(w io.Writer, dendrogram typedef.Dendrogram, prefix string) (err error) {
+	n := dendrogram.NumLeafs()
+	nodeIndex := make([]int, n-1)
+	for i, cluster := range dendrogram {
+		nodeIndex[cluster.Node-n] = i
+	}
+	id := func(node int) string {
+		if node < n {
+			return prefix + strconv.Itoa(node) + "X"
+		}
+		return "NODE" + strconv.Itoa(nodeIndex[node-n]+1) + "X"
+	}
+
+	heights := dendrogram.Heights()
+	scale := 1.0
+	for _, height := range heights {
+		scale = math.Max(scale, height)
+	}
+	buffer := bufio.NewWriter(w)
+	for i, cluster := range dendrogram {
+		buffer.WriteString(id(cluster.Node) + "\t" + id(cluster.Leafa) + "\t" + id(cluster.Leafb) + "\t" + formatFloat(1-heights[i]/scale) + "\n")
+	}
+	return buffer.Flush()
+}
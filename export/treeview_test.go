@@ -0,0 +1,116 @@
+package export
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTreeViewMatrix = [][]float64{
+	{1, 2},
+	{3, math.NaN()},
+	{5, 6},
+}
+
+var testRowDendrogram = typedef.Dendrogram{
+	{Leafa: 2, Leafb: 0, Lengtha: 0.1, Lengthb: 0.1, Node: 3, Height: 0.2, Size: 2},
+	{Leafa: 3, Leafb: 1, Lengtha: 0.15, Lengthb: 0.25, Node: 4, Height: 0.5, Size: 3},
+}
+
+var testColumnDendrogram = typedef.Dendrogram{
+	{Leafa: 1, Leafb: 0, Lengtha: 0.125, Lengthb: 0.125, Node: 2, Height: 0.25, Size: 2},
+}
+
+func TestTreeView(t *testing.T) {
+	rowNames := []string{"r0", "r1", "r2"}
+	columnNames := []string{"c0", "c1"}
+
+	// TEST1: write row and column trees.
+	var cdt, gtr, atr bytes.Buffer
+	err := TreeView(&cdt, &gtr, &atr, testTreeViewMatrix, rowNames, columnNames, testRowDendrogram, testColumnDendrogram)
+	wantCDT := "GID\tUNIQID\tNAME\tGWEIGHT\tc1\tc0\n" +
+		"AID\t\t\t\tARRY1X\tARRY0X\n" +
+		"EWEIGHT\t\t\t\t1\t1\n" +
+		"GENE2X\tr2\tr2\t1\t6\t5\n" +
+		"GENE0X\tr0\tr0\t1\t2\t1\n" +
+		"GENE1X\tr1\tr1\t1\t\t3\n"
+	wantGTR := "NODE1X\tGENE2X\tGENE0X\t0.8\n" +
+		"NODE2X\tNODE1X\tGENE1X\t0.5\n"
+	wantATR := "NODE1X\tARRY1X\tARRY0X\t0.75\n"
+	assert.Nil(t, err, "Should not return an error for valid input")
+	assert.Equal(t, wantCDT, cdt.String(), "Should write reordered data")
+	assert.Equal(t, wantGTR, gtr.String(), "Should write row tree")
+	assert.Equal(t, wantATR, atr.String(), "Should write column tree")
+
+	// TEST2: write only a row tree.
+	cdt.Reset()
+	gtr.Reset()
+	err = TreeView(&cdt, &gtr, nil, testTreeViewMatrix, rowNames, columnNames, testRowDendrogram, nil)
+	wantCDT = "GID\tUNIQID\tNAME\tGWEIGHT\tc0\tc1\n" +
+		"EWEIGHT\t\t\t\t1\t1\n" +
+		"GENE2X\tr2\tr2\t1\t5\t6\n" +
+		"GENE0X\tr0\tr0\t1\t1\t2\n" +
+		"GENE1X\tr1\tr1\t1\t3\t\n"
+	assert.Nil(t, err, "Should not return an error without a column tree")
+	assert.Equal(t, wantCDT, cdt.String(), "Should write data with columns in original order")
+	assert.Equal(t, wantGTR, gtr.String(), "Should write row tree without a column tree")
+
+	// TEST3: write only a column tree.
+	cdt.Reset()
+	atr.Reset()
+	err = TreeView(&cdt, nil, &atr, testTreeViewMatrix, rowNames, columnNames, nil, testColumnDendrogram)
+	wantCDT = "UNIQID\tNAME\tGWEIGHT\tc1\tc0\n" +
+		"AID\t\t\tARRY1X\tARRY0X\n" +
+		"EWEIGHT\t\t\t1\t1\n" +
+		"r0\tr0\t1\t2\t1\n" +
+		"r1\tr1\t1\t\t3\n" +
+		"r2\tr2\t1\t6\t5\n"
+	assert.Nil(t, err, "Should not return an error without a row tree")
+	assert.Equal(t, wantCDT, cdt.String(), "Should write data with rows in original order")
+
+	// TEST4: invalid input.
+	err = TreeView(&cdt, &gtr, &atr, testTreeViewMatrix, rowNames[:2], columnNames, testRowDendrogram, testColumnDendrogram)
+	assert.NotNil(t, err, "Should return an error when row names do not match the matrix")
+	err = TreeView(&cdt, &gtr, &atr, testTreeViewMatrix, []string{"r0", "r0", "r2"}, columnNames, testRowDendrogram, testColumnDendrogram)
+	assert.NotNil(t, err, "Should return an error when names are not unique")
+	err = TreeView(&cdt, &gtr, &atr, testTreeViewMatrix, rowNames, columnNames, testColumnDendrogram, testColumnDendrogram)
+	assert.NotNil(t, err, "Should return an error when the dendrogram does not match the matrix")
+	cdt.Reset()
+	atr.Reset()
+	err = TreeView(&cdt, nil, &atr, testTreeViewMatrix, rowNames, columnNames, testRowDendrogram, testColumnDendrogram)
+	assert.NotNil(t, err, "Should return an error when a tree writer is missing")
+	assert.Empty(t, cdt.String(), "Should not write data when a tree writer is missing")
+	assert.Empty(t, atr.String(), "Should not write a column tree when the row tree writer is missing")
+
+	// TEST5: heights above 1 are scaled by the highest merge.
+	gtr.Reset()
+	euclidean := typedef.Dendrogram{
+		{Leafa: 2, Leafb: 0, Lengtha: 1, Lengthb: 1, Node: 3, Height: 2, Size: 2},
+		{Leafa: 3, Leafb: 1, Lengtha: 1, Lengthb: 3, Node: 4, Height: 4, Size: 3},
+	}
+	err = TreeView(&cdt, &gtr, nil, testTreeViewMatrix, rowNames, columnNames, euclidean, nil)
+	wantGTR = "NODE1X\tGENE2X\tGENE0X\t0.5\n" +
+		"NODE2X\tNODE1X\tGENE1X\t0\n"
+	assert.Nil(t, err, "Should not return an error for heights above 1")
+	assert.Equal(t, wantGTR, gtr.String(), "Should write similarities scaled by the highest merge")
+}
+
+func TestTreeViewFiles(t *testing.T) {
+	// TEST1: write files with a common prefix.
+	prefix := filepath.Join(t.TempDir(), "heatmap")
+	err := TreeViewFiles(prefix, testTreeViewMatrix, []string{"r0", "r1", "r2"}, []string{"c0", "c1"}, testRowDendrogram, nil)
+	assert.Nil(t, err, "Should not return an error for valid input")
+	for _, extension := range []string{".cdt", ".gtr"} {
+		_, err = ioutil.ReadFile(prefix + extension)
+		assert.Nil(t, err, "Should write "+extension+" file")
+	}
+	_, err = ioutil.ReadFile(prefix + ".atr")
+	assert.NotNil(t, err, "Should not write an array tree without a column dendrogram")
+	gtr, _ := ioutil.ReadFile(prefix + ".gtr")
+	assert.Equal(t, "NODE1X\tGENE2X\tGENE0X\t0.8\nNODE2X\tNODE1X\tGENE1X\t0.5\n", string(gtr), "Should write row tree to file")
+}
@@ -3,37 +3,284 @@ package hclust
 
 import (
 	"github.com/knightjdr/hclust/cluster"
+	"github.com/knightjdr/hclust/convert"
+	"github.com/knightjdr/hclust/cut"
 	"github.com/knightjdr/hclust/dendrogram"
 	"github.com/knightjdr/hclust/distance"
+	"github.com/knightjdr/hclust/export"
+	"github.com/knightjdr/hclust/layout"
 	"github.com/knightjdr/hclust/optimize"
+	"github.com/knightjdr/hclust/plot"
 	"github.com/knightjdr/hclust/sort"
 	"github.com/knightjdr/hclust/tree"
 	"github.com/knightjdr/hclust/typedef"
+	"github.com/knightjdr/hclust/validity"
 )
 
+// AdjustedRand references the adjusted Rand index in the validity subpackage.
+var AdjustedRand = validity.AdjustedRand
+
+// Annotation is a colour bar drawn beside the leafs of a plot.
+type Annotation = plot.Annotation
+
+// CalinskiHarabasz references the Calinski-Harabasz index in the validity subpackage.
+var CalinskiHarabasz = validity.CalinskiHarabasz
+
+// CategoricalColors references the method for colouring labels from a palette in the plot subpackage.
+var CategoricalColors = plot.Categorical
+
 // Cluster references the main cluster method in the cluster subpackage.
 var Cluster = cluster.Cluster
 
-// Dendrogram is an array of SubClusters.
-type Dendrogram []SubCluster
+// ClusterContext references the cancellable cluster method in the cluster subpackage.
+var ClusterContext = cluster.ClusterContext
+
+// Coefficients holds the Lance-Williams coefficients for a flexible linkage.
+type Coefficients = cluster.Coefficients
+
+// ColorScale maps values to colours for heatmaps.
+type ColorScale = plot.ColorScale
+
+// ColorStop is a colour at a value of a ColorScale.
+type ColorStop = plot.Stop
+
+// Cophenetic references the method for calculating the cophenetic distance matrix in the dendrogram subpackage.
+var Cophenetic = dendrogram.Cophenetic
+
+// CopheneticCorrelation references the method for calculating the cophenetic correlation in the dendrogram subpackage.
+var CopheneticCorrelation = dendrogram.CopheneticCorrelation
+
+// CutDynamic references the dynamic tree cut method in the cut subpackage.
+var CutDynamic = cut.Dynamic
+
+// CutHeight references the method for cutting a dendrogram at a height in the cut subpackage.
+var CutHeight = cut.Height
+
+// CutInconsistent references the method for cutting a dendrogram by inconsistency coefficient in the cut subpackage.
+var CutInconsistent = cut.Inconsistent
+
+// CutK references the method for cutting a dendrogram into k clusters in the cut subpackage.
+var CutK = cut.K
+
+// CutKs references the method for cutting a dendrogram into several numbers of clusters in the cut subpackage.
+var CutKs = cut.Ks
+
+// DaviesBouldin references the Davies-Bouldin index in the validity subpackage.
+var DaviesBouldin = validity.DaviesBouldin
+
+// DefaultDendrogramPlotOptions references the default dendrogram plot options in the plot subpackage.
+var DefaultDendrogramPlotOptions = plot.DefaultDendrogramOptions
+
+// DefaultDendrogramTextOptions references the default text dendrogram options in the plot subpackage.
+var DefaultDendrogramTextOptions = plot.DefaultTextOptions
+
+// DefaultDynamicOptions references the default dynamic tree cut options in the cut subpackage.
+var DefaultDynamicOptions = cut.DefaultDynamicOptions
+
+// DefaultHeatmapOptions references the default heatmap plot options in the plot subpackage.
+var DefaultHeatmapOptions = plot.DefaultHeatmapOptions
+
+// DefaultLayoutOptions references the default dendrogram layout options in the layout subpackage.
+var DefaultLayoutOptions = layout.DefaultOptions
+
+// DefaultNewickOptions references the default newick formatting options in the tree subpackage.
+var DefaultNewickOptions = tree.DefaultOptions
+
+// DefaultPalette is the palette for cluster labels and categorical annotations.
+var DefaultPalette = plot.DefaultPalette
+
+// Dendrogram is an array of SubClusters with methods for looking up heights,
+// sizes, children, parents and leafs.
+type Dendrogram = typedef.Dendrogram
+
+// DendrogramLayout holds the node positions and elbows for drawing a dendrogram.
+type DendrogramLayout = layout.Layout
+
+// DendrogramPlotOptions holds the options for drawing a dendrogram.
+type DendrogramPlotOptions = plot.DendrogramOptions
+
+// DendrogramSVG references the SVG dendrogram renderer in the plot subpackage.
+var DendrogramSVG = plot.DendrogramSVG
+
+// DendrogramText references the text dendrogram printer in the plot subpackage.
+var DendrogramText = plot.Text
+
+// DendrogramTextOptions holds the options for printing a dendrogram as text.
+type DendrogramTextOptions = plot.TextOptions
 
 // Distance references the main distance method in the distance subpackage.
 var Distance = distance.Distance
 
+// DistanceContext references the cancellable distance method in the distance subpackage.
+var DistanceContext = distance.DistanceContext
+
+// DivergingScale references the diverging colour scale in the plot subpackage.
+var DivergingScale = plot.Diverging
+
+// Dunn references the Dunn index in the validity subpackage.
+var Dunn = validity.Dunn
+
+// DynamicOptions holds the options for a dynamic tree cut.
+type DynamicOptions = cut.DynamicOptions
+
+// ExportProperties holds the optional node properties for exported trees.
+type ExportProperties = export.Properties
+
+// Extract references the method for extracting a subtree by leaf names in the dendrogram subpackage.
+var Extract = dendrogram.Extract
+
+// FlexibleBeta references the flexible-beta (β = -0.25) Lance-Williams coefficients.
+var FlexibleBeta = cluster.FlexibleBeta
+
+// FowlkesMallows references the Fowlkes-Mallows index in the validity subpackage.
+var FowlkesMallows = validity.FowlkesMallows
+
+// FowlkesMallowsSweep references the Fowlkes-Mallows index for every cut of a dendrogram in the validity subpackage.
+var FowlkesMallowsSweep = validity.FowlkesMallowsSweep
+
+// FromData references the method for clustering a data matrix without a distance matrix in the cluster subpackage.
+var FromData = cluster.FromData
+
+// FromHclust references the method for converting R hclust components to a dendrogram in the convert subpackage.
+var FromHclust = convert.FromHclust
+
+// FromLinkage references the method for converting a scipy linkage matrix to a dendrogram in the convert subpackage.
+var FromLinkage = convert.FromLinkage
+
+// Gap references the gap statistic in the validity subpackage.
+var Gap = validity.Gap
+
 // GetNodeHeights gets the height for each dendrogram node by summing child branch lengths.
 var GetNodeHeight = dendrogram.GetNodeHeight
 
+// Hclust holds the components of an R hclust object.
+type Hclust = convert.Hclust
+
+// HeatmapOptions holds the options for drawing a clustered heatmap.
+type HeatmapOptions = plot.HeatmapOptions
+
+// HeatmapPNG references the PNG heatmap renderer in the plot subpackage.
+var HeatmapPNG = plot.HeatmapPNG
+
+// HeatmapSVG references the SVG heatmap renderer in the plot subpackage.
+var HeatmapSVG = plot.HeatmapSVG
+
+// Inconsistency holds the inconsistency statistics for a dendrogram node.
+type Inconsistency = dendrogram.Inconsistency
+
+// Inconsistent references the method for calculating inconsistency statistics in the dendrogram subpackage.
+var Inconsistent = dendrogram.Inconsistent
+
+// Iterator steps through the nodes of a Navigator's tree.
+type Iterator = dendrogram.Iterator
+
+// LanceWilliams references the flexible Lance-Williams linkage method in the cluster subpackage.
+var LanceWilliams = cluster.LanceWilliams
+
+// Layout references the method for computing dendrogram coordinates in the layout subpackage.
+var Layout = layout.Create
+
+// LayoutOptions holds the options for laying out a dendrogram.
+type LayoutOptions = layout.Options
+
+// Lazy references the method for clustering with on-demand distances in the cluster subpackage.
+var Lazy = cluster.Lazy
+
+// LazyDistance references the method for calculating distances on demand in the distance subpackage.
+var LazyDistance = distance.Lazy
+
+// LeafOrder gets the order of leafs in a dendrogram.
+var LeafOrder = dendrogram.LeafOrder
+
+// Linkage is a scipy linkage matrix.
+type Linkage = convert.Linkage
+
+// Navigator answers structural queries about a dendrogram.
+type Navigator = dendrogram.Navigator
+
+// NewickOptions holds the options for formatting a newick tree.
+type NewickOptions = tree.Options
+
+// NewNavigator references the method for creating a dendrogram Navigator in the dendrogram subpackage.
+var NewNavigator = dendrogram.NewNavigator
+
+// NormalizedMutualInformation references the normalized mutual information in the validity subpackage.
+var NormalizedMutualInformation = validity.NormalizedMutualInformation
+
 // Optimize references the main leaf optimization method in the optimize subpackage.
 var Optimize = optimize.Optimize
 
+// OptimizeContext references the cancellable leaf optimization method in the optimize subpackage.
+var OptimizeContext = optimize.OptimizeContext
+
+// ParseJSON references the JSON hierarchy reader in the export subpackage.
+var ParseJSON = export.ParseJSON
+
+// ParseNewick references the newick parser in the tree subpackage.
+var ParseNewick = tree.Parse
+
+// Progress reports the amount of work completed out of the total for long running methods.
+type Progress = typedef.Progress
+
+// Prune references the method for removing leafs by name in the dendrogram subpackage.
+var Prune = dendrogram.Prune
+
+// Purity references the purity index in the validity subpackage.
+var Purity = validity.Purity
+
+// ScanK references the method for calculating validity indices over a range of k in the validity subpackage.
+var ScanK = validity.Scan
+
+// SequentialScale references the sequential colour scale in the plot subpackage.
+var SequentialScale = plot.Sequential
+
+// Silhouette references the silhouette width in the validity subpackage.
+var Silhouette = validity.Silhouette
+
 // Sort references the main sort method in the sort subpackage
 var Sort = sort.Sort
 
 // SubCluster stores the node, distance and names of leafs for a subcluster.
 type SubCluster = typedef.SubCluster
 
-// TreeLayout contains a tree in newick format and the leaf order.
-type TreeLayout = tree.Tree
+// ToHclust references the method for converting a dendrogram to R hclust components in the convert subpackage.
+var ToHclust = convert.ToHclust
+
+// ToLinkage references the method for converting a dendrogram to a scipy linkage matrix in the convert subpackage.
+var ToLinkage = convert.ToLinkage
 
 // Tree references the main method for generating the newick tree in the tree subpackage.
 var Tree = tree.Create
+
+// TreeLayout contains a tree in newick format and the leaf order.
+type TreeLayout = tree.Tree
+
+// TreeWithOptions references the method for creating a formatted newick tree in the tree subpackage.
+var TreeWithOptions = tree.CreateWithOptions
+
+// ValidityScores holds the internal validity indices for one cut of a dendrogram.
+type ValidityScores = validity.Scores
+
+// WPGMA references the WPGMA Lance-Williams coefficients.
+var WPGMA = cluster.WPGMA
+
+// WriteJSON references the JSON hierarchy writer in the export subpackage.
+var WriteJSON = export.JSON
+
+// WriteNewick references the streaming newick writer in the tree subpackage.
+var WriteNewick = tree.Write
+
+// WriteNewickWithOptions references the streaming formatted newick writer in the tree subpackage.
+var WriteNewickWithOptions = tree.WriteWithOptions
+
+// WriteNexus references the Nexus tree writer in the export subpackage.
+var WriteNexus = export.Nexus
+
+// WritePhyloXML references the PhyloXML tree writer in the export subpackage.
+var WritePhyloXML = export.PhyloXML
+
+// WriteTreeView references the Java TreeView (CDT, GTR and ATR) writer in the export subpackage.
+var WriteTreeView = export.TreeView
+
+// WriteTreeViewFiles references the method for writing Java TreeView files in the export subpackage.
+var WriteTreeViewFiles = export.TreeViewFiles
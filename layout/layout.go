@@ -0,0 +1,195 @@
+// Package layout computes the coordinates for drawing a dendrogram.
+package layout
+
+import (
+	"errors"
+	"math"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// Options control how a dendrogram is laid out.
+type Options struct {
+	// Type is "rectangular", with leafs along the x axis and heights on the y
+	// axis, or "radial", with the top node at the centre and leafs around a
+	// circle.
+	Type string
+
+	// Levels truncates the tree to the top levels of merges. Internal nodes that
+	// are Levels merges below the top node are drawn as collapsed leafs. A value
+	// of zero draws the full tree.
+	Levels int
+}
+
+// DefaultOptions returns the options for a full rectangular layout.
+func DefaultOptions() Options {
+	return Options{
+		Type: "rectangular",
+	}
+}
+
+// Point is a position in a layout.
+type Point struct {
+	X, Y float64
+}
+
+// Position is the location of a node in a layout.
+type Position struct {
+	// Node is the leaf or node number.
+	Node int
+
+	// X and Y are the coordinates of the node. In a rectangular layout X is the
+	// position along the leafs (0 to the number of leafs drawn minus 1) and Y is
+	// the merge height (0 for leafs and collapsed nodes).
+	X, Y float64
+
+	// Angle (in radians, counter-clockwise from the x axis) and Radius are the
+	// polar coordinates of the node in a radial layout.
+	Angle, Radius float64
+
+	// Size is the number of leafs under the node.
+	Size int
+
+	// Collapsed is true for internal nodes drawn as leafs in a truncated layout.
+	Collapsed bool
+}
+
+// Segment is a line between two points. In a radial layout, arcs are drawn
+// around the centre from From to To in the direction of increasing angle.
+type Segment struct {
+	From, To Point
+	Arc      bool
+}
+
+// Elbow joins a node to its children with a segment from the left child up to
+// the node's height, a bar at the node's height and a segment from the bar
+// down to the right child. In a radial layout the bar is an arc.
+type Elbow [3]Segment
+
+// Layout holds the positions of the nodes drawn for a dendrogram and the
+// elbows joining them.
+type Layout struct {
+	// Leafs are the leafs and collapsed nodes from left to right.
+	Leafs []Position
+
+	// Nodes are the internal nodes drawn, in dendrogram order.
+	Nodes []Position
+
+	// Elbows join each node in Nodes to its children.
+	Elbows []Elbow
+
+	// Height is the maximum merge height.
+	Height float64
+}
+
+// Create lays out a dendrogram with its leafs in leaf order, as after
+// optimize.Optimize. Internal nodes are placed midway between their children
+// at their merge height, as for the icoord and dcoord of a scipy dendrogram.
+func Create(dendrogram typedef.Dendrogram, options Options) (layout Layout, err error) {
+	if options.Type != "rectangular" && options.Type != "radial" {
+		err = errors.New("Unknown layout type")
+		return
+	}
+	if options.Levels < 0 {
+		err = errors.New("The number of levels must not be negative")
+		return
+	}
+
+	n := dendrogram.NumLeafs()
+	heights := dendrogram.Heights()
+	sizes := dendrogram.Sizes()
+	nodeIndex := make([]int, n-1)
+	for i, cluster := range dendrogram {
+		nodeIndex[cluster.Node-n] = i
+	}
+	size := func(node int) int {
+		if node < n {
+			return 1
+		}
+		return sizes[nodeIndex[node-n]]
+	}
+
+	// Descend from the top node, left branches first, placing leafs and nodes at
+	// the truncation level along the x axis.
+	x := make([]float64, 2*n-1)
+	visible := make([]bool, 2*n-1)
+	depth := make([]int, 2*n-1)
+	stack := []int{2*n - 2}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		visible[node] = true
+		collapsed := node >= n && options.Levels > 0 && depth[node] == options.Levels
+		if node < n || collapsed {
+			x[node] = float64(len(layout.Leafs))
+			layout.Leafs = append(layout.Leafs, Position{
+				Node:      node,
+				X:         x[node],
+				Size:      size(node),
+				Collapsed: collapsed,
+			})
+			continue
+		}
+		cluster := dendrogram[nodeIndex[node-n]]
+		depth[cluster.Leafa] = depth[node] + 1
+		depth[cluster.Leafb] = depth[node] + 1
+		stack = append(stack, cluster.Leafb, cluster.Leafa)
+	}
+
+	// Children are merged before their parents, so nodes can be placed in
+	// dendrogram order.
+	y := make([]float64, 2*n-1)
+	for i, cluster := range dendrogram {
+		layout.Height = math.Max(layout.Height, heights[i])
+		if !visible[cluster.Leafa] {
+			continue
+		}
+		node := cluster.Node
+		x[node] = (x[cluster.Leafa] + x[cluster.Leafb]) / 2
+		y[node] = heights[i]
+		layout.Nodes = append(layout.Nodes, Position{
+			Node: node,
+			X:    x[node],
+			Y:    y[node],
+			Size: sizes[i],
+		})
+		a, b := cluster.Leafa, cluster.Leafb
+		layout.Elbows = append(layout.Elbows, Elbow{
+			{From: Point{x[a], y[a]}, To: Point{x[a], y[node]}},
+			{From: Point{x[a], y[node]}, To: Point{x[b], y[node]}},
+			{From: Point{x[b], y[node]}, To: Point{x[b], y[b]}},
+		})
+	}
+
+	if options.Type == "radial" {
+		layout.toRadial()
+	}
+	return
+}
+
+// toRadial converts a rectangular layout to a radial one. Leafs are spaced
+// evenly around a circle with a radius of the maximum merge height and the
+// radius of each node is the maximum height minus its merge height.
+func (layout *Layout) toRadial() {
+	step := 2 * math.Pi / float64(len(layout.Leafs))
+	convert := func(p Point) (point Point, angle, radius float64) {
+		angle = p.X * step
+		radius = layout.Height - p.Y
+		return Point{radius * math.Cos(angle), radius * math.Sin(angle)}, angle, radius
+	}
+	for _, positions := range [][]Position{layout.Leafs, layout.Nodes} {
+		for i := range positions {
+			point, angle, radius := convert(Point{positions[i].X, positions[i].Y})
+			positions[i].X, positions[i].Y = point.X, point.Y
+			positions[i].Angle, positions[i].Radius = angle, radius
+		}
+	}
+	for i := range layout.Elbows {
+		for j := range layout.Elbows[i] {
+			segment := &layout.Elbows[i][j]
+			segment.From, _, _ = convert(segment.From)
+			segment.To, _, _ = convert(segment.To)
+			segment.Arc = j == 1
+		}
+	}
+}
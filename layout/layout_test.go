@@ -0,0 +1,112 @@
+package layout
+
+import (
+	"math"
+	"testing"
+
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+var testDendrogram = typedef.Dendrogram{
+	{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5, Height: 2, Size: 2},
+	{Leafa: 5, Leafb: 1, Lengtha: 2.95, Lengthb: 3.95, Node: 6, Height: 7.9, Size: 3},
+	{Leafa: 3, Leafb: 2, Lengtha: 6.1, Lengthb: 6.1, Node: 7, Height: 12.2, Size: 2},
+	{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.56, Node: 8, Height: 17.32, Size: 5},
+}
+
+func TestCreate(t *testing.T) {
+	// TEST1: rectangular layout.
+	layout, err := Create(testDendrogram, DefaultOptions())
+	wantLeafs := []Position{
+		{Node: 0, X: 0, Size: 1},
+		{Node: 4, X: 1, Size: 1},
+		{Node: 1, X: 2, Size: 1},
+		{Node: 3, X: 3, Size: 1},
+		{Node: 2, X: 4, Size: 1},
+	}
+	wantNodes := []Position{
+		{Node: 5, X: 0.5, Y: 2, Size: 2},
+		{Node: 6, X: 1.25, Y: 7.9, Size: 3},
+		{Node: 7, X: 3.5, Y: 12.2, Size: 2},
+		{Node: 8, X: 2.375, Y: 17.32, Size: 5},
+	}
+	assert.Nil(t, err, "Should not return an error for a valid layout")
+	assert.Equal(t, wantLeafs, layout.Leafs, "Should place leafs in leaf order")
+	assert.Equal(t, wantNodes, layout.Nodes, "Should place nodes between their children at their height")
+	assert.Equal(t, 17.32, layout.Height, "Should return maximum height")
+	wantElbow := Elbow{
+		{From: Point{1.25, 7.9}, To: Point{1.25, 17.32}},
+		{From: Point{1.25, 17.32}, To: Point{3.5, 17.32}},
+		{From: Point{3.5, 17.32}, To: Point{3.5, 12.2}},
+	}
+	assert.Len(t, layout.Elbows, 4, "Should return an elbow for each node")
+	assert.Equal(t, wantElbow, layout.Elbows[3], "Should join node to its children")
+
+	// TEST2: truncated layout.
+	options := DefaultOptions()
+	options.Levels = 1
+	layout, _ = Create(testDendrogram, options)
+	wantLeafs = []Position{
+		{Node: 6, X: 0, Size: 3, Collapsed: true},
+		{Node: 7, X: 1, Size: 2, Collapsed: true},
+	}
+	wantElbows := []Elbow{
+		{
+			{From: Point{0, 0}, To: Point{0, 17.32}},
+			{From: Point{0, 17.32}, To: Point{1, 17.32}},
+			{From: Point{1, 17.32}, To: Point{1, 0}},
+		},
+	}
+	assert.Equal(t, wantLeafs, layout.Leafs, "Should collapse nodes at the truncation level")
+	assert.Equal(t, []Position{{Node: 8, X: 0.5, Y: 17.32, Size: 5}}, layout.Nodes, "Should only place nodes above the truncation level")
+	assert.Equal(t, wantElbows, layout.Elbows, "Should join nodes to collapsed nodes at height 0")
+
+	options.Levels = 2
+	layout, _ = Create(testDendrogram, options)
+	wantLeafs = []Position{
+		{Node: 5, X: 0, Size: 2, Collapsed: true},
+		{Node: 1, X: 1, Size: 1},
+		{Node: 3, X: 2, Size: 1},
+		{Node: 2, X: 3, Size: 1},
+	}
+	wantNodes = []Position{
+		{Node: 6, X: 0.5, Y: 7.9, Size: 3},
+		{Node: 7, X: 2.5, Y: 12.2, Size: 2},
+		{Node: 8, X: 1.5, Y: 17.32, Size: 5},
+	}
+	assert.Equal(t, wantLeafs, layout.Leafs, "Should keep leafs above the truncation level")
+	assert.Equal(t, wantNodes, layout.Nodes, "Should place nodes of truncated tree")
+
+	// TEST3: radial layout.
+	options = DefaultOptions()
+	options.Type = "radial"
+	layout, _ = Create(testDendrogram, options)
+	step := 2 * math.Pi / 5
+	assert.InDelta(t, 17.32, layout.Leafs[0].X, 1e-9, "Should place first leaf on the x axis")
+	assert.InDelta(t, 0, layout.Leafs[0].Y, 1e-9, "Should place first leaf on the x axis")
+	assert.InDelta(t, 17.32, layout.Leafs[2].Radius, 1e-9, "Should place leafs at the maximum height")
+	assert.InDelta(t, 2*step, layout.Leafs[2].Angle, 1e-9, "Should space leafs evenly")
+	assert.InDelta(t, 0, layout.Nodes[3].Radius, 1e-9, "Should place top node at the centre")
+	node := layout.Nodes[2]
+	assert.InDelta(t, 3.5*step, node.Angle, 1e-9, "Should place node between its children")
+	assert.InDelta(t, 5.12, node.Radius, 1e-9, "Should place node at the maximum height minus its height")
+	assert.InDelta(t, 5.12*math.Cos(3.5*step), node.X, 1e-9, "Should convert node x to cartesian coordinates")
+	assert.InDelta(t, 5.12*math.Sin(3.5*step), node.Y, 1e-9, "Should convert node y to cartesian coordinates")
+	elbow := layout.Elbows[2]
+	assert.Equal(t, []bool{false, true, false}, []bool{elbow[0].Arc, elbow[1].Arc, elbow[2].Arc}, "Should draw bar as an arc")
+	assert.InDelta(t, 5.12*math.Cos(3*step), elbow[1].From.X, 1e-9, "Should start arc at left child angle")
+	assert.InDelta(t, 5.12*math.Sin(4*step), elbow[1].To.Y, 1e-9, "Should end arc at right child angle")
+
+	// TEST4: single leaf.
+	layout, err = Create(typedef.Dendrogram{}, DefaultOptions())
+	assert.Nil(t, err, "Should not return an error for a single leaf")
+	assert.Equal(t, []Position{{Node: 0, X: 0, Size: 1}}, layout.Leafs, "Should place single leaf")
+	assert.Empty(t, layout.Nodes, "Should not place nodes for a single leaf")
+
+	// TEST5: invalid options.
+	_, err = Create(testDendrogram, Options{Type: "circle"})
+	assert.NotNil(t, err, "Should return an error for unknown layout type")
+	_, err = Create(testDendrogram, Options{Type: "radial", Levels: -1})
+	assert.NotNil(t, err, "Should return an error for negative levels")
+}
@@ -0,0 +1,11 @@
+package matrixop
+
+// Copy returns a deep copy of a 2D matrix.
+func Copy(matrix [][]float64) (copied [][]float64) {
+	copied = make([][]float64, len(matrix))
+	for i, row := range matrix {
+		copied[i] = make([]float64, len(row))
+		copy(copied[i], row)
+	}
+	return
+}
@@ -0,0 +1,28 @@
+package matrixop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopy(t *testing.T) {
+	matrix := [][]float64{
+		{0, 10, 23},
+		{10, 0, 17.8},
+		{23, 17.8, 0},
+	}
+
+	// TEST1: copy a matrix.
+	want := [][]float64{
+		{0, 10, 23},
+		{10, 0, 17.8},
+		{23, 17.8, 0},
+	}
+	copied := Copy(matrix)
+	assert.Equal(t, want, copied, "Matrix not copied correctly")
+
+	// TEST2: modifying the copy should not modify the original.
+	copied[0][1] = 5
+	assert.Equal(t, want, matrix, "Original matrix should not be modified")
+}
@@ -2,6 +2,7 @@
 package optimize
 
 import (
+	"context"
 	"math"
 	"sort"
 
@@ -105,7 +106,17 @@ func shouldIgnore(threshold int) (ignoreFunc func(comparisons int) bool) {
 
 // Optimize optimizes the leaf ordering of a dendrogram using the method
 // of Bar-Joseph, et al. 2001.
-func Optimize(dendrogram []typedef.SubCluster, dist [][]float64, ignore int) (optimized []typedef.SubCluster) {
+func Optimize(dendrogram typedef.Dendrogram, dist [][]float64, ignore int) (optimized typedef.Dendrogram) {
+	optimized, _ = OptimizeContext(context.Background(), dendrogram, dist, ignore, nil)
+	return
+}
+
+// OptimizeContext is the same as Optimize but stops with the context's error if
+// it is cancelled. Cancellation is checked after each node is scored and after
+// each node is reordered. If progress is not nil it is called at the same points
+// with the number of steps completed and the total number of steps (twice the
+// number of nodes).
+func OptimizeContext(ctx context.Context, dendrogram typedef.Dendrogram, dist [][]float64, ignore int, progress typedef.Progress) (optimized typedef.Dendrogram, err error) {
 	// Number of nodes.
 	n := len(dendrogram)
 
@@ -149,7 +160,7 @@ func Optimize(dendrogram []typedef.SubCluster, dist [][]float64, ignore int) (op
 	ignoreFunc := shouldIgnore(ignore)
 
 	// Calculate optimal ordering score for each node.
-	for _, cluster := range dendrogram {
+	for clusterIndex, cluster := range dendrogram {
 		node := cluster.Node
 		numLeafsA := len(nodeLeafs[node].a)
 		numLeafsB := len(nodeLeafs[node].b)
@@ -200,10 +211,17 @@ func Optimize(dendrogram []typedef.SubCluster, dist [][]float64, ignore int) (op
 				m[node][bLeaf][aLeaf] = optScore
 			}
 		}
+
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		if progress != nil {
+			progress(clusterIndex+1, 2*n)
+		}
 	}
 
 	// Re-order dendrogram.
-	optimized = make([]typedef.SubCluster, n)
+	optimized = make(typedef.Dendrogram, n)
 	copy(optimized, dendrogram)
 
 	// Constraints contains the left and right contraints for each node. -1 is used
@@ -258,6 +276,8 @@ func Optimize(dendrogram []typedef.SubCluster, dist [][]float64, ignore int) (op
 				Lengtha: dendrogram[i].Lengthb,
 				Lengthb: dendrogram[i].Lengtha,
 				Node:    dendrogram[i].Node,
+				Height:  dendrogram[i].Height,
+				Size:    dendrogram[i].Size,
 			}
 		} else {
 			optimized[i] = typedef.SubCluster{
@@ -266,6 +286,8 @@ func Optimize(dendrogram []typedef.SubCluster, dist [][]float64, ignore int) (op
 				Lengtha: dendrogram[i].Lengtha,
 				Lengthb: dendrogram[i].Lengthb,
 				Node:    dendrogram[i].Node,
+				Height:  dendrogram[i].Height,
+				Size:    dendrogram[i].Size,
 			}
 		}
 
@@ -276,6 +298,13 @@ func Optimize(dendrogram []typedef.SubCluster, dist [][]float64, ignore int) (op
 		if optimized[i].Leafb > n {
 			constrain[optimized[i].Leafb] = constraints{left: -1, right: outerB}
 		}
+
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		if progress != nil {
+			progress(2*n-i, 2*n)
+		}
 	}
 
 	return
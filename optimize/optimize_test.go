@@ -1,6 +1,7 @@
 package optimize
 
 import (
+	"context"
 	"testing"
 
 	"github.com/knightjdr/hclust/typedef"
@@ -52,7 +53,7 @@ func TestOptimize(t *testing.T) {
 		{0.4, 0.42, 0.73, 0.41, 0, 0.74},
 		{0.72, 0.71, 0.125, 0.73, 0.74, 0},
 	}
-	want := []typedef.SubCluster{
+	want := typedef.Dendrogram{
 		{Leafa: 0, Leafb: 3, Lengtha: 0.05, Lengthb: 0.05, Node: 6},
 		{Leafa: 2, Leafb: 5, Lengtha: 0.075, Lengthb: 0.075, Node: 7},
 		{Leafa: 6, Leafb: 1, Lengtha: 0.05, Lengthb: 0.1, Node: 8},
@@ -76,7 +77,7 @@ func TestOptimize(t *testing.T) {
 		{17.6, 17.32, 12.2, 0, 17.4},
 		{2, 7.9, 17.7, 17.4, 0},
 	}
-	want = []typedef.SubCluster{
+	want = typedef.Dendrogram{
 		{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5},
 		{Leafa: 5, Leafb: 1, Lengtha: 2.95, Lengthb: 3.95, Node: 6},
 		{Leafa: 3, Leafb: 2, Lengtha: 6.1, Lengthb: 6.1, Node: 7},
@@ -85,3 +86,37 @@ func TestOptimize(t *testing.T) {
 	optimized = Optimize(dendrogram, dist, 0)
 	assert.Equal(t, want, optimized, "Dendrogram not optimized correctly")
 }
+
+func TestOptimizeContext(t *testing.T) {
+	dendrogram := []typedef.SubCluster{
+		{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5},
+		{Leafa: 1, Leafb: 5, Lengtha: 3.95, Lengthb: 2.95, Node: 6},
+		{Leafa: 2, Leafb: 3, Lengtha: 6.1, Lengthb: 6.1, Node: 7},
+		{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.56, Node: 8},
+	}
+	dist := [][]float64{
+		{0, 7.91, 17.7, 17.6, 2},
+		{7.91, 0, 17.33, 17.32, 7.9},
+		{17.7, 17.33, 0, 12.2, 17.5},
+		{17.6, 17.32, 12.2, 0, 17.4},
+		{2, 7.9, 17.7, 17.4, 0},
+	}
+
+	// TEST1: progress is reported after every node is scored and reordered.
+	completed := make([]int, 0)
+	progress := func(done, total int) {
+		assert.Equal(t, 8, total, "Total steps not correct")
+		completed = append(completed, done)
+	}
+	optimized, err := OptimizeContext(context.Background(), dendrogram, dist, 0, progress)
+	assert.Nil(t, err, "Optimization should not return an error")
+	assert.Equal(t, Optimize(dendrogram, dist, 0), optimized, "Dendrogram should match Optimize")
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, completed, "Progress not reported correctly")
+
+	// TEST2: cancelled context stops optimization.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	optimized, err = OptimizeContext(ctx, dendrogram, dist, 0, nil)
+	assert.Equal(t, context.Canceled, err, "Cancelled context should return error")
+	assert.Nil(t, optimized, "Cancelled context should not return a dendrogram")
+}
@@ -0,0 +1,106 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+)
+
+// Stop is a colour at a value of a colour scale.
+type Stop struct {
+	Value float64
+	Color color.RGBA
+}
+
+// ColorScale maps values to colours by interpolating between stops, which must
+// be in increasing order of value. Values outside the stops get the colour of
+// the nearest stop and missing values (NaN) get the Missing colour.
+type ColorScale struct {
+	Stops   []Stop
+	Missing color.RGBA
+}
+
+var missingColor = color.RGBA{204, 204, 204, 255}
+
+// Sequential returns a scale from white at min to dark blue at max.
+func Sequential(min, max float64) ColorScale {
+	return ColorScale{
+		Stops: []Stop{
+			{Value: min, Color: color.RGBA{247, 251, 255, 255}},
+			{Value: max, Color: color.RGBA{8, 48, 107, 255}},
+		},
+		Missing: missingColor,
+	}
+}
+
+// Diverging returns a scale from blue at min through light grey at centre to
+// red at max.
+func Diverging(min, centre, max float64) ColorScale {
+	return ColorScale{
+		Stops: []Stop{
+			{Value: min, Color: color.RGBA{33, 102, 172, 255}},
+			{Value: centre, Color: color.RGBA{247, 247, 247, 255}},
+			{Value: max, Color: color.RGBA{178, 24, 43, 255}},
+		},
+		Missing: missingColor,
+	}
+}
+
+// Color returns the colour for a value.
+func (scale ColorScale) Color(value float64) color.RGBA {
+	stops := scale.Stops
+	if math.IsNaN(value) || len(stops) == 0 {
+		return scale.Missing
+	}
+	if value <= stops[0].Value {
+		return stops[0].Color
+	}
+	for i := 1; i < len(stops); i++ {
+		if value > stops[i].Value {
+			continue
+		}
+		low, high := stops[i-1], stops[i]
+		t := (value - low.Value) / (high.Value - low.Value)
+		mix := func(a, b uint8) uint8 {
+			return uint8(math.Round(float64(a) + (t * (float64(b) - float64(a)))))
+		}
+		return color.RGBA{
+			R: mix(low.Color.R, high.Color.R),
+			G: mix(low.Color.G, high.Color.G),
+			B: mix(low.Color.B, high.Color.B),
+			A: mix(low.Color.A, high.Color.A),
+		}
+	}
+	return stops[len(stops)-1].Color
+}
+
+// DefaultPalette is the palette for cluster labels and categorical annotations.
+var DefaultPalette = []color.RGBA{
+	{31, 119, 180, 255},
+	{255, 127, 14, 255},
+	{44, 160, 44, 255},
+	{214, 39, 40, 255},
+	{148, 103, 189, 255},
+	{140, 86, 75, 255},
+	{227, 119, 194, 255},
+	{127, 127, 127, 255},
+	{188, 189, 34, 255},
+	{23, 190, 207, 255},
+}
+
+// Categorical returns a colour for each label from a palette, cycling through
+// the palette for labels greater than its length. Labels less than 1 (such as
+// leafs not assigned to a module) get the missing colour.
+func Categorical(labels []int, palette []color.RGBA) []color.RGBA {
+	colors := make([]color.RGBA, len(labels))
+	for i, label := range labels {
+		colors[i] = paletteColor(label, palette, missingColor)
+	}
+	return colors
+}
+
+func paletteColor(label int, palette []color.RGBA, fallback color.RGBA) color.RGBA {
+	if label < 1 || len(palette) == 0 {
+		return fallback
+	}
+	return palette[(label-1)%len(palette)]
+}
@@ -0,0 +1,35 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorScale(t *testing.T) {
+	// TEST1: sequential scale.
+	scale := Sequential(0, 10)
+	assert.Equal(t, color.RGBA{247, 251, 255, 255}, scale.Color(0), "Should return low colour at minimum")
+	assert.Equal(t, color.RGBA{8, 48, 107, 255}, scale.Color(10), "Should return high colour at maximum")
+	assert.Equal(t, color.RGBA{128, 150, 181, 255}, scale.Color(5), "Should interpolate between stops")
+	assert.Equal(t, color.RGBA{247, 251, 255, 255}, scale.Color(-5), "Should clamp values below the scale")
+	assert.Equal(t, color.RGBA{8, 48, 107, 255}, scale.Color(50), "Should clamp values above the scale")
+	assert.Equal(t, missingColor, scale.Color(math.NaN()), "Should return missing colour for NaN")
+
+	// TEST2: diverging scale with an off-centre centre.
+	scale = Diverging(-1, 0, 4)
+	assert.Equal(t, color.RGBA{247, 247, 247, 255}, scale.Color(0), "Should return centre colour at centre")
+	assert.Equal(t, color.RGBA{140, 175, 210, 255}, scale.Color(-0.5), "Should interpolate below centre")
+	assert.Equal(t, color.RGBA{213, 136, 145, 255}, scale.Color(2), "Should interpolate above centre")
+
+	// TEST3: scale without stops.
+	assert.Equal(t, color.RGBA{1, 2, 3, 255}, ColorScale{Missing: color.RGBA{1, 2, 3, 255}}.Color(1), "Should return missing colour without stops")
+}
+
+func TestCategorical(t *testing.T) {
+	palette := []color.RGBA{{1, 0, 0, 255}, {0, 1, 0, 255}}
+	want := []color.RGBA{{1, 0, 0, 255}, {0, 1, 0, 255}, {1, 0, 0, 255}, missingColor}
+	assert.Equal(t, want, Categorical([]int{1, 2, 3, 0}, palette), "Should cycle through palette and use missing colour for unassigned labels")
+}
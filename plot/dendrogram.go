@@ -0,0 +1,184 @@
+package plot
+
+import (
+	"errors"
+	"image/color"
+	"math"
+
+	"github.com/knightjdr/hclust/layout"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// DendrogramOptions control how a dendrogram is drawn. Sizes are in pixels.
+type DendrogramOptions struct {
+	// Layout is the type of layout ("rectangular" or "radial") and the number of
+	// levels to draw.
+	Layout layout.Options
+
+	// Orientation of a rectangular layout: "top" for the top node at the top and
+	// leafs along the bottom, or "left" for the top node on the left and leafs
+	// down the right side.
+	Orientation string
+
+	// LeafSpacing is the distance between leafs in a rectangular layout.
+	LeafSpacing float64
+
+	// TreeSize is the distance from the top node to the leafs, which is the
+	// radius of a radial layout.
+	TreeSize float64
+
+	// BranchColor and BranchWidth are the colour and line width of branches.
+	BranchColor color.RGBA
+	BranchWidth float64
+
+	// Clusters are optional cluster labels for each leaf, such as from cut.K.
+	// Branches joining leafs with the same label are drawn in the label's colour
+	// from Palette.
+	Clusters []int
+	Palette  []color.RGBA
+
+	// Annotations are colour bars drawn beside the leafs, each AnnotationSize
+	// wide. Annotation names are not drawn in radial layouts.
+	Annotations    []Annotation
+	AnnotationSize float64
+
+	// FontSize is the size of leaf and annotation labels. Labels are not drawn
+	// when it is 0.
+	FontSize float64
+}
+
+// DefaultDendrogramOptions returns the options for a rectangular dendrogram
+// with the top node at the top.
+func DefaultDendrogramOptions() DendrogramOptions {
+	return DendrogramOptions{
+		Layout:         layout.DefaultOptions(),
+		Orientation:    "top",
+		LeafSpacing:    12,
+		TreeSize:       200,
+		BranchColor:    black,
+		BranchWidth:    1,
+		Palette:        DefaultPalette,
+		AnnotationSize: 10,
+		FontSize:       10,
+	}
+}
+
+// dendrogramFigure draws a dendrogram and its leaf names (which may be nil).
+func dendrogramFigure(dendrogram typedef.Dendrogram, names []string, options DendrogramOptions) (f *figure, err error) {
+	n := dendrogram.NumLeafs()
+	if names != nil && len(names) != n {
+		err = errors.New("The names vector must have the same dimension as the leaf number")
+		return
+	}
+	if err = validateClusters(options.Clusters, n); err != nil {
+		return
+	}
+	if err = validateAnnotations(options.Annotations, n); err != nil {
+		return
+	}
+	if options.Orientation != "top" && options.Orientation != "left" {
+		err = errors.New("Unknown orientation")
+		return
+	}
+	if options.LeafSpacing <= 0 || options.TreeSize <= 0 {
+		err = errors.New("The leaf spacing and tree size must be greater than 0")
+		return
+	}
+	l, err := layout.Create(dendrogram, options.Layout)
+	if err != nil {
+		return
+	}
+
+	style := branchStyle{
+		color:    options.BranchColor,
+		width:    options.BranchWidth,
+		clusters: options.Clusters,
+		palette:  options.Palette,
+	}
+	colors := elbowColors(dendrogram, l, style)
+	labels := leafLabels(l, names)
+	leafs := make([]int, len(l.Leafs))
+	for i, leaf := range l.Leafs {
+		leafs[i] = leaf.Node
+		if leaf.Collapsed {
+			leafs[i] = -1
+		}
+	}
+
+	// Distance from the top node to the labels.
+	extent := options.TreeSize
+	if len(options.Annotations) > 0 {
+		extent += gap + (float64(len(options.Annotations)) * options.AnnotationSize)
+	}
+	labelSize := float64(0)
+	if options.FontSize > 0 {
+		labelSize = maxTextWidth(labels, options.FontSize)
+	}
+
+	f = &figure{}
+	if options.Layout.Type == "radial" {
+		size := extent + gap + labelSize
+		f.width, f.height = 2*(margin+size), 2*(margin+size)
+		centre := margin + size
+		f.drawRadial(l, colors, options.BranchWidth, centre, centre, options.TreeSize)
+		f.drawRadialLeafs(l, leafs, labels, options, centre)
+		return
+	}
+
+	along := float64(len(leafs)) * options.LeafSpacing
+	across := extent + gap + labelSize
+	along += annotationNameWidth(options.Annotations, options.FontSize)
+	if options.Orientation == "left" {
+		f.width, f.height = across+(2*margin), along+(2*margin)
+		f.drawRectangular(l, colors, options.BranchWidth, margin, margin, options.LeafSpacing, options.TreeSize, "left")
+		f.drawAnnotations(options.Annotations, leafs, margin+options.TreeSize+gap, margin, options.LeafSpacing, options.AnnotationSize, options.FontSize, "left")
+		if options.FontSize > 0 {
+			f.drawLabels(labels, margin+extent+gap, margin, options.LeafSpacing, options.FontSize, "left")
+		}
+		return
+	}
+	f.width, f.height = along+(2*margin), across+(2*margin)
+	f.drawRectangular(l, colors, options.BranchWidth, margin, margin, options.LeafSpacing, options.TreeSize, "top")
+	f.drawAnnotations(options.Annotations, leafs, margin, margin+options.TreeSize+gap, options.LeafSpacing, options.AnnotationSize, options.FontSize, "top")
+	if options.FontSize > 0 {
+		f.drawLabels(labels, margin, margin+extent+gap, options.LeafSpacing, options.FontSize, "top")
+	}
+	return
+}
+
+// drawRadialLeafs draws annotation rings and leaf labels around a radial
+// layout. Labels are rotated to point away from the centre and flipped on the
+// left side so they are not upside down.
+func (f *figure) drawRadialLeafs(l layout.Layout, leafs []int, labels []string, options DendrogramOptions, centre float64) {
+	step := 2 * math.Pi / float64(len(leafs))
+	radius := options.TreeSize + gap
+	for _, annotation := range options.Annotations {
+		for i, leaf := range leafs {
+			if leaf < 0 {
+				continue
+			}
+			angle := l.Leafs[i].Angle
+			f.arc(centre, centre, radius+(options.AnnotationSize/2), angle-(step/2), angle+(step/2), options.AnnotationSize, annotation.Colors[leaf])
+		}
+		radius += options.AnnotationSize
+	}
+	if options.FontSize <= 0 {
+		return
+	}
+	if len(options.Annotations) > 0 {
+		radius += gap
+	}
+	for i, label := range labels {
+		angle := l.Leafs[i].Angle
+		rotate := -angle * 180 / math.Pi
+		anchor := "start"
+		if math.Cos(angle) < -1e-9 {
+			rotate += 180
+			anchor = "end"
+		}
+		for rotate <= -180 {
+			rotate += 360
+		}
+		f.text(centre+(radius*math.Cos(angle)), centre-(radius*math.Sin(angle)), options.FontSize, rotate, anchor, label)
+	}
+}
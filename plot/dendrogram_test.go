@@ -0,0 +1,62 @@
+package plot
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDendrogramFigure(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e"}
+
+	// TEST1: top node at the top.
+	f, err := dendrogramFigure(testDendrogram, names, DefaultDendrogramOptions())
+	assert.Nil(t, err, "Should not return an error for a valid dendrogram")
+	assert.Equal(t, float64(80), f.width, "Should size figure for leafs")
+	assert.Equal(t, float64(230), f.height, "Should size figure for tree and labels")
+	assert.Len(t, f.shapes, 17, "Should draw each elbow and label")
+	assert.Equal(t, text{16, 214, 10, 90, "start", "a", black}, f.shapes[12], "Should draw labels below leafs")
+
+	// TEST2: top node on the left with annotations.
+	options := DefaultDendrogramOptions()
+	options.Orientation = "left"
+	red := color.RGBA{255, 0, 0, 255}
+	options.Annotations = []Annotation{{Name: "group", Colors: []color.RGBA{red, red, red, red, red}}}
+	f, err = dendrogramFigure(testDendrogram, names, options)
+	assert.Nil(t, err, "Should not return an error with annotations")
+	assert.Equal(t, float64(244), f.width, "Should size figure for tree, annotations and labels")
+	assert.Equal(t, float64(114), f.height, "Should size figure for leafs and annotation names")
+	assert.Equal(t, rect{214, 10, 10, 12, red}, f.shapes[12], "Should draw annotation beside leafs")
+	assert.Equal(t, text{228, 16, 10, 0, "start", "a", black}, f.shapes[18], "Should draw labels after annotations")
+
+	// TEST3: radial layout.
+	options = DefaultDendrogramOptions()
+	options.Layout.Type = "radial"
+	f, err = dendrogramFigure(testDendrogram, names, options)
+	assert.Nil(t, err, "Should not return an error for a radial layout")
+	assert.Equal(t, float64(440), f.width, "Should size radial figure")
+	assert.Equal(t, text{424, 220, 10, 0, "start", "a", black}, f.shapes[11], "Should draw labels around the tree")
+
+	// TEST4: truncated layout without names.
+	options = DefaultDendrogramOptions()
+	options.Layout.Levels = 1
+	f, _ = dendrogramFigure(testDendrogram, nil, options)
+	assert.Equal(t, text{16, 214, 10, 90, "start", "(3)", black}, f.shapes[3], "Should label collapsed nodes")
+
+	// TEST5: invalid input.
+	_, err = dendrogramFigure(testDendrogram, names[:2], DefaultDendrogramOptions())
+	assert.NotNil(t, err, "Should return an error when names do not match leafs")
+	options = DefaultDendrogramOptions()
+	options.Clusters = []int{1}
+	_, err = dendrogramFigure(testDendrogram, names, options)
+	assert.NotNil(t, err, "Should return an error when clusters do not match leafs")
+	options = DefaultDendrogramOptions()
+	options.Orientation = "bottom"
+	_, err = dendrogramFigure(testDendrogram, names, options)
+	assert.NotNil(t, err, "Should return an error for unknown orientation")
+	options = DefaultDendrogramOptions()
+	options.Annotations = []Annotation{{Colors: []color.RGBA{red}}}
+	_, err = dendrogramFigure(testDendrogram, names, options)
+	assert.NotNil(t, err, "Should return an error when annotations do not match leafs")
+}
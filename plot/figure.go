@@ -0,0 +1,290 @@
+// Package plot draws dendrograms and clustered heatmaps.
+package plot
+
+import (
+	"errors"
+	"image/color"
+	"math"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/knightjdr/hclust/layout"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// Annotation is a colour bar drawn beside the leafs, such as for a sample
+// group. Colors has one colour for each leaf, in the original leaf order.
+type Annotation struct {
+	Name   string
+	Colors []color.RGBA
+}
+
+// Shapes in a figure. Coordinates are in pixels from the top left corner.
+type line struct {
+	x1, y1, x2, y2 float64
+	width          float64
+	color          color.RGBA
+}
+
+// arc is drawn counter-clockwise on screen from start to end, with angles in
+// radians counter-clockwise from the x axis (as for a radial layout).
+type arc struct {
+	cx, cy, radius float64
+	start, end     float64
+	width          float64
+	color          color.RGBA
+}
+
+type rect struct {
+	x, y, width, height float64
+	color               color.RGBA
+}
+
+// text is anchored at its start, middle or end and rotated (in degrees,
+// clockwise on screen) around its anchor point. Text is vertically centred on
+// y.
+type text struct {
+	x, y    float64
+	size    float64
+	rotate  float64
+	anchor  string
+	content string
+	color   color.RGBA
+}
+
+// grid is a matrix of values drawn as square cells coloured by a scale, with
+// the first cell at x, y.
+type grid struct {
+	x, y, size float64
+	values     [][]float64
+	scale      ColorScale
+}
+
+// figure is a list of shapes to draw, in order, and the size of the drawing.
+type figure struct {
+	width, height float64
+	shapes        []interface{}
+}
+
+var black = color.RGBA{0, 0, 0, 255}
+
+const (
+	margin = 10
+	gap    = 4
+)
+
+func (f *figure) line(x1, y1, x2, y2, width float64, c color.RGBA) {
+	f.shapes = append(f.shapes, line{x1, y1, x2, y2, width, c})
+}
+
+func (f *figure) arc(cx, cy, radius, start, end, width float64, c color.RGBA) {
+	f.shapes = append(f.shapes, arc{cx, cy, radius, start, end, width, c})
+}
+
+func (f *figure) rect(x, y, width, height float64, c color.RGBA) {
+	f.shapes = append(f.shapes, rect{x, y, width, height, c})
+}
+
+func (f *figure) text(x, y, size, rotate float64, anchor, content string) {
+	f.shapes = append(f.shapes, text{x, y, size, rotate, anchor, content, black})
+}
+
+// textWidth estimates the width of text from the number of characters.
+func textWidth(content string, size float64) float64 {
+	return 0.6 * size * float64(utf8.RuneCountInString(content))
+}
+
+func maxTextWidth(contents []string, size float64) (width float64) {
+	for _, content := range contents {
+		width = math.Max(width, textWidth(content, size))
+	}
+	return
+}
+
+// branchStyle is how the branches of a tree are drawn.
+type branchStyle struct {
+	color    color.RGBA
+	width    float64
+	clusters []int
+	palette  []color.RGBA
+}
+
+// elbowColors gets the colour of each elbow in a layout. When cluster labels
+// are given, a node whose leafs all have the same (positive) label is drawn in
+// that label's colour from the palette, as is its elbow.
+func elbowColors(dendrogram typedef.Dendrogram, l layout.Layout, style branchStyle) []color.RGBA {
+	colors := make([]color.RGBA, len(l.Nodes))
+	if style.clusters == nil {
+		for i := range colors {
+			colors[i] = style.color
+		}
+		return colors
+	}
+
+	n := dendrogram.NumLeafs()
+	label := make([]int, 2*n-1)
+	copy(label, style.clusters)
+	for _, cluster := range dendrogram {
+		if label[cluster.Leafa] == label[cluster.Leafb] {
+			label[cluster.Node] = label[cluster.Leafa]
+		}
+	}
+	for i, node := range l.Nodes {
+		colors[i] = paletteColor(label[node.Node], style.palette, style.color)
+	}
+	return colors
+}
+
+// leafLabels gets the label of each leaf in a layout: the leaf name, or the
+// number of leafs under a collapsed node in parentheses.
+func leafLabels(l layout.Layout, names []string) []string {
+	labels := make([]string, len(l.Leafs))
+	for i, leaf := range l.Leafs {
+		if leaf.Collapsed {
+			labels[i] = "(" + formatNumber(float64(leaf.Size)) + ")"
+		} else if names != nil {
+			labels[i] = names[leaf.Node]
+		}
+	}
+	return labels
+}
+
+// drawRectangular draws a rectangular layout. With orientation "top" the top
+// node is at y, the leafs are at y+depth and leafs are spaced along the x axis
+// from x. With orientation "left" the top node is at x and the leafs are at
+// x+depth, spaced along the y axis from y.
+func (f *figure) drawRectangular(l layout.Layout, colors []color.RGBA, width float64, x, y, spacing, depth float64, orientation string) {
+	position := func(p layout.Point) (float64, float64) {
+		along := (p.X + 0.5) * spacing
+		distance := depth
+		if l.Height > 0 {
+			distance = depth * (1 - (p.Y / l.Height))
+		}
+		if orientation == "left" {
+			return x + distance, y + along
+		}
+		return x + along, y + distance
+	}
+	for i, elbow := range l.Elbows {
+		for _, segment := range elbow {
+			x1, y1 := position(segment.From)
+			x2, y2 := position(segment.To)
+			f.line(x1, y1, x2, y2, width, colors[i])
+		}
+	}
+}
+
+// drawRadial draws a radial layout centred on cx, cy with leafs at the radius.
+func (f *figure) drawRadial(l layout.Layout, colors []color.RGBA, width float64, cx, cy, radius float64) {
+	scale := float64(1)
+	if l.Height > 0 {
+		scale = radius / l.Height
+	}
+	for i, elbow := range l.Elbows {
+		for _, segment := range elbow {
+			if !segment.Arc {
+				f.line(cx+(segment.From.X*scale), cy-(segment.From.Y*scale), cx+(segment.To.X*scale), cy-(segment.To.Y*scale), width, colors[i])
+				continue
+			}
+			arcRadius := math.Hypot(segment.From.X, segment.From.Y) * scale
+			if arcRadius == 0 {
+				continue
+			}
+			start := math.Atan2(segment.From.Y, segment.From.X)
+			end := math.Atan2(segment.To.Y, segment.To.X)
+			if start < 0 {
+				start += 2 * math.Pi
+			}
+			for end < start {
+				end += 2 * math.Pi
+			}
+			f.arc(cx, cy, arcRadius, start, end, width, colors[i])
+		}
+	}
+}
+
+// formatNumber formats a value to two decimal places, dropping trailing zeros.
+func formatNumber(value float64) string {
+	rounded := math.Round(value*100) / 100
+	if rounded == 0 {
+		// Avoid writing negative zero.
+		rounded = 0
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// drawAnnotations draws annotation bars for the leafs at each position
+// (collapsed nodes, with a leaf of -1, are skipped). With orientation "top" the
+// bars are rows from y, with leafs spaced along the x axis from x. With
+// orientation "left" the bars are columns from x, with leafs spaced along the y
+// axis from y. Annotation names are drawn after the last leaf when fontSize is
+// greater than 0.
+func (f *figure) drawAnnotations(annotations []Annotation, leafs []int, x, y, spacing, band, fontSize float64, orientation string) {
+	for k, annotation := range annotations {
+		across := float64(k) * band
+		for i, leaf := range leafs {
+			if leaf < 0 {
+				continue
+			}
+			along := float64(i) * spacing
+			if orientation == "left" {
+				f.rect(x+across, y+along, band, spacing, annotation.Colors[leaf])
+			} else {
+				f.rect(x+along, y+across, spacing, band, annotation.Colors[leaf])
+			}
+		}
+		if fontSize <= 0 || annotation.Name == "" {
+			continue
+		}
+		end := (float64(len(leafs)) * spacing) + gap
+		if orientation == "left" {
+			f.text(x+across+(band/2), y+end, fontSize, 90, "start", annotation.Name)
+		} else {
+			f.text(x+end, y+across+(band/2), fontSize, 0, "start", annotation.Name)
+		}
+	}
+}
+
+// drawLabels draws leaf labels. With orientation "top" the labels are written
+// down from y, with leafs spaced along the x axis from x. With orientation
+// "left" the labels are written across from x, with leafs spaced along the y
+// axis from y.
+func (f *figure) drawLabels(labels []string, x, y, spacing, fontSize float64, orientation string) {
+	for i, label := range labels {
+		along := (float64(i) + 0.5) * spacing
+		if orientation == "left" {
+			f.text(x, y+along, fontSize, 0, "start", label)
+		} else {
+			f.text(x+along, y, fontSize, 90, "start", label)
+		}
+	}
+}
+
+// annotationNameWidth is the space needed after the last leaf for annotation
+// names.
+func annotationNameWidth(annotations []Annotation, fontSize float64) float64 {
+	if fontSize <= 0 || len(annotations) == 0 {
+		return 0
+	}
+	names := make([]string, len(annotations))
+	for i, annotation := range annotations {
+		names[i] = annotation.Name
+	}
+	return gap + maxTextWidth(names, fontSize)
+}
+
+func validateAnnotations(annotations []Annotation, n int) (err error) {
+	for _, annotation := range annotations {
+		if len(annotation.Colors) != n {
+			return errors.New("Annotations must have one colour for each leaf")
+		}
+	}
+	return
+}
+
+func validateClusters(clusters []int, n int) (err error) {
+	if clusters != nil && len(clusters) != n {
+		return errors.New("There must be one cluster label for each leaf")
+	}
+	return
+}
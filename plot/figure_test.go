@@ -0,0 +1,125 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/knightjdr/hclust/layout"
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+var testDendrogram = typedef.Dendrogram{
+	{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5, Height: 2, Size: 2},
+	{Leafa: 5, Leafb: 1, Lengtha: 2.95, Lengthb: 3.95, Node: 6, Height: 7.9, Size: 3},
+	{Leafa: 3, Leafb: 2, Lengtha: 6.1, Lengthb: 6.1, Node: 7, Height: 12.2, Size: 2},
+	{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.56, Node: 8, Height: 17.32, Size: 5},
+}
+
+var testPair = typedef.Dendrogram{
+	{Leafa: 0, Leafb: 1, Lengtha: 1, Lengthb: 1, Node: 2, Height: 2, Size: 2},
+}
+
+func TestElbowColors(t *testing.T) {
+	l, _ := layout.Create(testDendrogram, layout.DefaultOptions())
+	palette := []color.RGBA{{1, 0, 0, 255}, {0, 1, 0, 255}}
+
+	// TEST1: single branch colour.
+	style := branchStyle{color: black}
+	assert.Equal(t, []color.RGBA{black, black, black, black}, elbowColors(testDendrogram, l, style), "Should use branch colour without clusters")
+
+	// TEST2: colour branches by cluster.
+	style = branchStyle{color: black, clusters: []int{1, 1, 2, 2, 1}, palette: palette}
+	want := []color.RGBA{palette[0], palette[0], palette[1], black}
+	assert.Equal(t, want, elbowColors(testDendrogram, l, style), "Should colour branches within a cluster")
+}
+
+func TestLeafLabels(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e"}
+	options := layout.DefaultOptions()
+	options.Levels = 2
+	l, _ := layout.Create(testDendrogram, options)
+	assert.Equal(t, []string{"(2)", "b", "d", "c"}, leafLabels(l, names), "Should label leafs and collapsed nodes")
+	assert.Equal(t, []string{"(2)", "", "", ""}, leafLabels(l, nil), "Should only label collapsed nodes without names")
+}
+
+func TestDrawRectangular(t *testing.T) {
+	l, _ := layout.Create(testPair, layout.DefaultOptions())
+	colors := []color.RGBA{black}
+
+	// TEST1: top node at the top.
+	f := &figure{}
+	f.drawRectangular(l, colors, 1, 10, 10, 10, 100, "top")
+	want := []interface{}{
+		line{15, 110, 15, 10, 1, black},
+		line{15, 10, 25, 10, 1, black},
+		line{25, 10, 25, 110, 1, black},
+	}
+	assert.Equal(t, want, f.shapes, "Should draw elbow with top node at the top")
+
+	// TEST2: top node on the left.
+	f = &figure{}
+	f.drawRectangular(l, colors, 1, 10, 10, 10, 100, "left")
+	want = []interface{}{
+		line{110, 15, 10, 15, 1, black},
+		line{10, 15, 10, 25, 1, black},
+		line{10, 25, 110, 25, 1, black},
+	}
+	assert.Equal(t, want, f.shapes, "Should draw elbow with top node on the left")
+}
+
+func TestDrawRadial(t *testing.T) {
+	options := layout.DefaultOptions()
+	options.Type = "radial"
+	l, _ := layout.Create(testDendrogram, options)
+	colors := []color.RGBA{black, black, black, black}
+	f := &figure{}
+	f.drawRadial(l, colors, 1, 200, 200, 100)
+
+	arcs := make([]arc, 0)
+	for _, shape := range f.shapes {
+		if a, ok := shape.(arc); ok {
+			arcs = append(arcs, a)
+		}
+	}
+	assert.Len(t, f.shapes, 11, "Should not draw an arc for the node at the centre")
+	assert.Len(t, arcs, 3, "Should draw an arc for each node away from the centre")
+	step := 2 * math.Pi / 5
+	assert.InDelta(t, 100*5.12/17.32, arcs[2].radius, 1e-9, "Should scale arc radius")
+	assert.InDelta(t, 3*step, arcs[2].start, 1e-9, "Should start arc at left child")
+	assert.InDelta(t, 4*step, arcs[2].end, 1e-9, "Should end arc at right child")
+	first := f.shapes[0].(line)
+	assert.InDelta(t, 300, first.x1, 1e-9, "Should place first leaf to the right of the centre")
+	assert.InDelta(t, 200, first.y1, 1e-9, "Should place first leaf level with the centre")
+}
+
+func TestDrawAnnotations(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	annotations := []Annotation{{Name: "group", Colors: []color.RGBA{red, blue}}}
+
+	// TEST1: annotation row below leafs.
+	f := &figure{}
+	f.drawAnnotations(annotations, []int{1, 0}, 10, 20, 12, 8, 10, "top")
+	want := []interface{}{
+		rect{10, 20, 12, 8, blue},
+		rect{22, 20, 12, 8, red},
+		text{38, 24, 10, 0, "start", "group", black},
+	}
+	assert.Equal(t, want, f.shapes, "Should draw annotation row in leaf order")
+
+	// TEST2: annotation column beside leafs, skipping collapsed nodes.
+	f = &figure{}
+	f.drawAnnotations(annotations, []int{-1, 0}, 10, 20, 12, 8, 0, "left")
+	want = []interface{}{
+		rect{10, 32, 8, 12, red},
+	}
+	assert.Equal(t, want, f.shapes, "Should draw annotation column without names")
+}
+
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "1.23", formatNumber(1.2345), "Should round to two decimal places")
+	assert.Equal(t, "2", formatNumber(2.0001), "Should drop trailing zeros")
+	assert.Equal(t, "0", formatNumber(-0.001), "Should not write negative zero")
+}
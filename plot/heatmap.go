@@ -0,0 +1,271 @@
+package plot
+
+import (
+	"errors"
+	"image/color"
+	"math"
+
+	"github.com/knightjdr/hclust/dendrogram"
+	"github.com/knightjdr/hclust/layout"
+	"github.com/knightjdr/hclust/sort"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// HeatmapOptions control how a clustered heatmap is drawn. Sizes are in pixels.
+type HeatmapOptions struct {
+	// CellSize is the width and height of each cell.
+	CellSize float64
+
+	// Scale is the colour scale for cells. When it has no stops a sequential
+	// scale over the range of the matrix is used.
+	Scale ColorScale
+
+	// Legend draws the colour scale below the heatmap.
+	Legend bool
+
+	// TreeSize is the distance from the top node to the leafs of the row and
+	// column dendrograms.
+	TreeSize float64
+
+	// BranchColor and BranchWidth are the colour and line width of branches.
+	BranchColor color.RGBA
+	BranchWidth float64
+
+	// RowClusters and ColumnClusters are optional cluster labels for each row
+	// and column. Branches joining leafs with the same label are drawn in the
+	// label's colour from Palette.
+	RowClusters    []int
+	ColumnClusters []int
+	Palette        []color.RGBA
+
+	// RowAnnotations and ColumnAnnotations are colour bars drawn between the
+	// dendrograms and the heatmap, each AnnotationSize wide.
+	RowAnnotations    []Annotation
+	ColumnAnnotations []Annotation
+	AnnotationSize    float64
+
+	// FontSize is the size of row, column, annotation and legend labels. Labels
+	// are not drawn when it is 0.
+	FontSize float64
+}
+
+// DefaultHeatmapOptions returns the options for a heatmap with a legend.
+func DefaultHeatmapOptions() HeatmapOptions {
+	return HeatmapOptions{
+		CellSize:       12,
+		Legend:         true,
+		TreeSize:       100,
+		BranchColor:    black,
+		BranchWidth:    1,
+		Palette:        DefaultPalette,
+		AnnotationSize: 10,
+		FontSize:       10,
+	}
+}
+
+const legendWidth = 120
+
+// heatmapAxis is the rows or columns of a heatmap in the order they are drawn.
+type heatmapAxis struct {
+	colors []color.RGBA
+	layout layout.Layout
+	leafs  []int
+	names  []string
+}
+
+func newHeatmapAxis(dend typedef.Dendrogram, names []string, dimension int, style branchStyle) (axis heatmapAxis, err error) {
+	if len(names) != dimension {
+		err = errors.New("The names vector must have the same dimension as the data matrix")
+		return
+	}
+	unique := make(map[string]bool, len(names))
+	for _, name := range names {
+		if unique[name] {
+			err = errors.New("Names must be unique")
+			return
+		}
+		unique[name] = true
+	}
+	if err = validateClusters(style.clusters, dimension); err != nil {
+		return
+	}
+
+	if dend == nil {
+		axis.leafs = make([]int, dimension)
+		for i := range axis.leafs {
+			axis.leafs[i] = i
+		}
+	} else {
+		if dend.NumLeafs() != dimension {
+			err = errors.New("The dendrogram must have one leaf for each row or column of the data matrix")
+			return
+		}
+		axis.leafs = dendrogram.LeafOrder(dend)
+		if axis.layout, err = layout.Create(dend, layout.DefaultOptions()); err != nil {
+			return
+		}
+		axis.colors = elbowColors(dend, axis.layout, style)
+	}
+	axis.names = make([]string, dimension)
+	for i, leaf := range axis.leafs {
+		axis.names[i] = names[leaf]
+	}
+	return
+}
+
+// heatmapFigure draws a data matrix with its rows and columns reordered by
+// their dendrograms (which may be nil). The row dendrogram is drawn on the
+// left, the column dendrogram on top and row and column names on the right and
+// bottom.
+func heatmapFigure(matrix [][]float64, rowNames, columnNames []string, rowDendrogram, columnDendrogram typedef.Dendrogram, options HeatmapOptions) (f *figure, err error) {
+	if len(matrix) == 0 || len(matrix[0]) == 0 {
+		err = errors.New("The data matrix must not be empty")
+		return
+	}
+	for _, row := range matrix {
+		if len(row) != len(matrix[0]) {
+			err = errors.New("All rows of the data matrix must have the same length")
+			return
+		}
+	}
+	if options.CellSize <= 0 || options.TreeSize <= 0 {
+		err = errors.New("The cell size and tree size must be greater than 0")
+		return
+	}
+	style := branchStyle{color: options.BranchColor, width: options.BranchWidth, palette: options.Palette}
+	style.clusters = options.RowClusters
+	rows, err := newHeatmapAxis(rowDendrogram, rowNames, len(matrix), style)
+	if err != nil {
+		return
+	}
+	style.clusters = options.ColumnClusters
+	columns, err := newHeatmapAxis(columnDendrogram, columnNames, len(matrix[0]), style)
+	if err != nil {
+		return
+	}
+	if err = validateAnnotations(options.RowAnnotations, len(matrix)); err != nil {
+		return
+	}
+	if err = validateAnnotations(options.ColumnAnnotations, len(matrix[0])); err != nil {
+		return
+	}
+
+	// Reorder the matrix as for the CDT files read by TreeView.
+	sorted, err := sort.Sort(matrix, rowNames, rows.names, "row")
+	if err != nil {
+		return
+	}
+	if sorted, err = sort.Sort(sorted, columnNames, columns.names, "column"); err != nil {
+		return
+	}
+	scale := options.Scale
+	if len(scale.Stops) == 0 {
+		scale = Sequential(matrixRange(matrix))
+	}
+
+	// Position the heatmap after the dendrograms and annotations.
+	band := options.AnnotationSize
+	heatmapX, heatmapY := float64(margin), float64(margin)
+	if rowDendrogram != nil {
+		heatmapX += options.TreeSize + gap
+	}
+	if len(options.RowAnnotations) > 0 {
+		heatmapX += (float64(len(options.RowAnnotations)) * band) + gap
+	}
+	if columnDendrogram != nil {
+		heatmapY += options.TreeSize + gap
+	}
+	if len(options.ColumnAnnotations) > 0 {
+		heatmapY += (float64(len(options.ColumnAnnotations)) * band) + gap
+	}
+	cell := options.CellSize
+	heatmapWidth := float64(len(columns.leafs)) * cell
+	heatmapHeight := float64(len(rows.leafs)) * cell
+
+	// Space after the heatmap for labels.
+	right := annotationNameWidth(options.ColumnAnnotations, options.FontSize)
+	below := annotationNameWidth(options.RowAnnotations, options.FontSize)
+	if options.FontSize > 0 {
+		right = math.Max(right, gap+maxTextWidth(rows.names, options.FontSize))
+		below = math.Max(below, gap+maxTextWidth(columns.names, options.FontSize))
+	}
+	legendY := heatmapY + heatmapHeight + below + gap
+	f = &figure{
+		width:  heatmapX + heatmapWidth + right + margin,
+		height: heatmapY + heatmapHeight + below + margin,
+	}
+	if options.Legend {
+		f.width = math.Max(f.width, heatmapX+legendWidth+margin)
+		f.height += gap + band
+		if options.FontSize > 0 {
+			f.height += gap + options.FontSize
+		}
+	}
+
+	if rowDendrogram != nil {
+		f.drawRectangular(rows.layout, rows.colors, options.BranchWidth, margin, heatmapY, cell, options.TreeSize, "left")
+	}
+	if columnDendrogram != nil {
+		f.drawRectangular(columns.layout, columns.colors, options.BranchWidth, heatmapX, margin, cell, options.TreeSize, "top")
+	}
+	rowAnnotationX := heatmapX - gap - (float64(len(options.RowAnnotations)) * band)
+	columnAnnotationY := heatmapY - gap - (float64(len(options.ColumnAnnotations)) * band)
+	f.drawAnnotations(options.RowAnnotations, rows.leafs, rowAnnotationX, heatmapY, cell, band, options.FontSize, "left")
+	f.drawAnnotations(options.ColumnAnnotations, columns.leafs, heatmapX, columnAnnotationY, cell, band, options.FontSize, "top")
+	f.shapes = append(f.shapes, grid{heatmapX, heatmapY, cell, sorted, scale})
+	if options.FontSize > 0 {
+		f.drawLabels(rows.names, heatmapX+heatmapWidth+gap, heatmapY, cell, options.FontSize, "left")
+		f.drawLabels(columns.names, heatmapX, heatmapY+heatmapHeight+gap, cell, options.FontSize, "top")
+	}
+	if options.Legend {
+		f.drawLegend(scale, heatmapX, legendY, band, options.FontSize)
+	}
+	return
+}
+
+// drawLegend draws a colour scale as a gradient from its first to its last
+// stop, with the value of each stop below it.
+func (f *figure) drawLegend(scale ColorScale, x, y, height, fontSize float64) {
+	stops := scale.Stops
+	low, high := stops[0].Value, stops[len(stops)-1].Value
+	steps := 60
+	step := float64(legendWidth) / float64(steps)
+	for i := 0; i < steps; i++ {
+		value := low + ((high - low) * (float64(i) + 0.5) / float64(steps))
+		f.rect(x+(float64(i)*step), y, step, height, scale.Color(value))
+	}
+	if fontSize <= 0 {
+		return
+	}
+	for i, stop := range stops {
+		position := float64(0)
+		if high > low {
+			position = legendWidth * (stop.Value - low) / (high - low)
+		}
+		anchor := "middle"
+		if i == 0 {
+			anchor = "start"
+		} else if i == len(stops)-1 {
+			anchor = "end"
+		}
+		f.text(x+position, y+height+gap+(fontSize/2), fontSize, 0, anchor, formatNumber(stop.Value))
+	}
+}
+
+// matrixRange gets the minimum and maximum values of a matrix, ignoring
+// missing values.
+func matrixRange(matrix [][]float64) (min, max float64) {
+	min, max = math.Inf(1), math.Inf(-1)
+	for _, row := range matrix {
+		for _, value := range row {
+			if !math.IsNaN(value) {
+				min = math.Min(min, value)
+				max = math.Max(max, value)
+			}
+		}
+	}
+	if math.IsInf(min, 1) {
+		return 0, 0
+	}
+	return
+}
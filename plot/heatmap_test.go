@@ -0,0 +1,110 @@
+package plot
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMatrix = [][]float64{
+	{1, 2},
+	{3, math.NaN()},
+	{5, 6},
+}
+
+var testRowDendrogram = typedef.Dendrogram{
+	{Leafa: 2, Leafb: 0, Lengtha: 0.1, Lengthb: 0.1, Node: 3, Height: 0.2, Size: 2},
+	{Leafa: 3, Leafb: 1, Lengtha: 0.15, Lengthb: 0.25, Node: 4, Height: 0.5, Size: 3},
+}
+
+var testColumnDendrogram = typedef.Dendrogram{
+	{Leafa: 1, Leafb: 0, Lengtha: 0.125, Lengthb: 0.125, Node: 2, Height: 0.25, Size: 2},
+}
+
+// gridColors gets the colours of heatmap cells from a figure, by row.
+func gridColors(f *figure) []color.RGBA {
+	colors := make([]color.RGBA, 0)
+	for _, shape := range f.shapes {
+		if g, ok := shape.(grid); ok {
+			for _, row := range g.values {
+				for _, value := range row {
+					colors = append(colors, g.scale.Color(value))
+				}
+			}
+		}
+	}
+	return colors
+}
+
+func TestHeatmapFigure(t *testing.T) {
+	rowNames := []string{"r0", "r1", "r2"}
+	columnNames := []string{"c0", "c1"}
+
+	// TEST1: row and column dendrograms.
+	f, err := heatmapFigure(testMatrix, rowNames, columnNames, testRowDendrogram, testColumnDendrogram, DefaultHeatmapOptions())
+	scale := Sequential(1, 6)
+	want := []color.RGBA{
+		scale.Color(6), scale.Color(5),
+		scale.Color(2), scale.Color(1),
+		scale.Color(math.NaN()), scale.Color(3),
+	}
+	assert.Nil(t, err, "Should not return an error for valid input")
+	assert.Equal(t, float64(244), f.width, "Should size figure for heatmap, labels and legend")
+	assert.Equal(t, float64(204), f.height, "Should size figure for heatmap, labels and legend")
+	assert.Equal(t, want, gridColors(f), "Should draw cells in dendrogram order")
+	heatmap := f.shapes[9].(grid)
+	assert.Equal(t, []float64{114, 114, 12}, []float64{heatmap.x, heatmap.y, heatmap.size}, "Should place heatmap after dendrograms")
+	assert.Contains(t, f.shapes, text{142, 120, 10, 0, "start", "r2", black}, "Should draw row labels on the right")
+	assert.Contains(t, f.shapes, text{120, 154, 10, 90, "start", "c1", black}, "Should draw column labels below")
+	assert.Contains(t, f.shapes, text{234, 189, 10, 0, "end", "6", black}, "Should label legend")
+
+	// TEST2: no dendrograms, a diverging scale and annotations.
+	options := DefaultHeatmapOptions()
+	options.Scale = Diverging(0, 3, 6)
+	options.Legend = false
+	options.FontSize = 0
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	options.RowAnnotations = []Annotation{{Colors: []color.RGBA{red, blue, red}}}
+	f, err = heatmapFigure(testMatrix, rowNames, columnNames, nil, nil, options)
+	want = []color.RGBA{
+		options.Scale.Color(1), options.Scale.Color(2),
+		options.Scale.Color(3), options.Scale.Color(math.NaN()),
+		options.Scale.Color(5), options.Scale.Color(6),
+	}
+	assert.Nil(t, err, "Should not return an error without dendrograms")
+	assert.Equal(t, float64(58), f.width, "Should size figure for heatmap and annotations")
+	assert.Equal(t, float64(56), f.height, "Should size figure for heatmap")
+	assert.Equal(t, want, gridColors(f), "Should draw cells in original order")
+	assert.Equal(t, rect{10, 22, 10, 12, blue}, f.shapes[1], "Should draw row annotations before heatmap")
+
+	// TEST3: branches coloured by cluster.
+	options = DefaultHeatmapOptions()
+	options.RowClusters = []int{1, 2, 1}
+	options.Palette = []color.RGBA{red, blue}
+	f, _ = heatmapFigure(testMatrix, rowNames, columnNames, testRowDendrogram, nil, options)
+	assert.Equal(t, red, f.shapes[0].(line).color, "Should colour branches within a cluster")
+	assert.Equal(t, black, f.shapes[3].(line).color, "Should draw branches joining clusters in branch colour")
+
+	// TEST4: invalid input.
+	_, err = heatmapFigure([][]float64{}, rowNames, columnNames, nil, nil, DefaultHeatmapOptions())
+	assert.NotNil(t, err, "Should return an error for an empty matrix")
+	_, err = heatmapFigure([][]float64{{1, 2}, {3}, {5, 6}}, rowNames, columnNames, nil, nil, DefaultHeatmapOptions())
+	assert.NotNil(t, err, "Should return an error for a ragged matrix")
+	_, err = heatmapFigure(testMatrix, rowNames[:2], columnNames, nil, nil, DefaultHeatmapOptions())
+	assert.NotNil(t, err, "Should return an error when row names do not match the matrix")
+	_, err = heatmapFigure(testMatrix, []string{"r0", "r0", "r2"}, columnNames, testRowDendrogram, nil, DefaultHeatmapOptions())
+	assert.NotNil(t, err, "Should return an error when names are not unique")
+	_, err = heatmapFigure(testMatrix, rowNames, columnNames, testColumnDendrogram, nil, DefaultHeatmapOptions())
+	assert.NotNil(t, err, "Should return an error when a dendrogram does not match the matrix")
+}
+
+func TestMatrixRange(t *testing.T) {
+	min, max := matrixRange(testMatrix)
+	assert.Equal(t, []float64{1, 6}, []float64{min, max}, "Should ignore missing values")
+	min, max = matrixRange([][]float64{{math.NaN()}})
+	assert.Equal(t, []float64{0, 0}, []float64{min, max}, "Should return zero range without values")
+}
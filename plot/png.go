@@ -0,0 +1,92 @@
+package plot
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// HeatmapPNG draws a data matrix as a clustered heatmap in PNG format, for
+// matrices too large for SVG. Rows and columns are ordered as for HeatmapSVG
+// and the dendrograms (which may be nil), annotations and legend are drawn in
+// the same positions. Labels are not drawn, so FontSize is ignored. A cell size
+// below 1 draws several cells in each pixel, with later rows and columns drawn
+// over earlier ones.
+func HeatmapPNG(w io.Writer, matrix [][]float64, rowNames, columnNames []string, rowDendrogram, columnDendrogram typedef.Dendrogram, options HeatmapOptions) (err error) {
+	options.FontSize = 0
+	f, err := heatmapFigure(matrix, rowNames, columnNames, rowDendrogram, columnDendrogram, options)
+	if err != nil {
+		return
+	}
+	return png.Encode(w, rasterize(f))
+}
+
+// rasterize draws the lines, rectangles and grids of a figure on a white
+// image. Text and arcs are not drawn.
+func rasterize(f *figure) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(f.width)), int(math.Ceil(f.height))))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	for _, shape := range f.shapes {
+		switch s := shape.(type) {
+		case line:
+			rasterizeLine(img, s)
+		case rect:
+			fillRect(img, s.x, s.y, s.x+s.width, s.y+s.height, s.color)
+		case grid:
+			for i, row := range s.values {
+				y := s.y + (float64(i) * s.size)
+				for j, value := range row {
+					x := s.x + (float64(j) * s.size)
+					fillRect(img, x, y, x+s.size, y+s.size, s.scale.Color(value))
+				}
+			}
+		}
+	}
+	return img
+}
+
+// rasterizeLine draws a line with square ends. Horizontal and vertical lines
+// are drawn as rectangles and other lines as a series of squares.
+func rasterizeLine(img *image.RGBA, l line) {
+	half := l.width / 2
+	if l.x1 == l.x2 || l.y1 == l.y2 {
+		fillRect(img, math.Min(l.x1, l.x2)-half, math.Min(l.y1, l.y2)-half, math.Max(l.x1, l.x2)+half, math.Max(l.y1, l.y2)+half, l.color)
+		return
+	}
+	steps := int(math.Ceil(2 * math.Hypot(l.x2-l.x1, l.y2-l.y1)))
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x := l.x1 + (t * (l.x2 - l.x1))
+		y := l.y1 + (t * (l.y2 - l.y1))
+		fillRect(img, x-half, y-half, x+half, y+half, l.color)
+	}
+}
+
+// fillRect fills the pixels between x0, y0 and x1, y1, rounded to the nearest
+// pixel boundary. Rectangles are at least one pixel wide and high so thin lines
+// and small cells are not lost.
+func fillRect(img *image.RGBA, x0, y0, x1, y1 float64, c color.RGBA) {
+	r := image.Rect(int(math.Round(x0)), int(math.Round(y0)), int(math.Round(x1)), int(math.Round(y1)))
+	if r.Dx() == 0 {
+		r.Max.X++
+	}
+	if r.Dy() == 0 {
+		r.Max.Y++
+	}
+	r = r.Intersect(img.Bounds())
+	if c.A < 255 {
+		draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Over)
+		return
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		start := img.PixOffset(r.Min.X, y)
+		for offset := start; offset < start+(4*r.Dx()); offset += 4 {
+			img.Pix[offset], img.Pix[offset+1], img.Pix[offset+2], img.Pix[offset+3] = c.R, c.G, c.B, 255
+		}
+	}
+}
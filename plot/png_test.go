@@ -0,0 +1,66 @@
+package plot
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeatmapPNG(t *testing.T) {
+	rowNames := []string{"r0", "r1", "r2"}
+	columnNames := []string{"c0", "c1"}
+	white := color.RGBA{255, 255, 255, 255}
+
+	// TEST1: sample pixels of a heatmap with dendrograms and a legend.
+	var buffer bytes.Buffer
+	err := HeatmapPNG(&buffer, testMatrix, rowNames, columnNames, testRowDendrogram, testColumnDendrogram, DefaultHeatmapOptions())
+	assert.Nil(t, err, "Should not return an error for a valid heatmap")
+	img, err := png.Decode(&buffer)
+	assert.Nil(t, err, "Should write a valid PNG")
+	scale := Sequential(1, 6)
+	pixel := func(x, y int) color.RGBA {
+		return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+	}
+	assert.Equal(t, image.Rect(0, 0, 244, 174), img.Bounds(), "Should size image without space for labels")
+	assert.Equal(t, white, pixel(2, 2), "Should draw white background")
+	assert.Equal(t, scale.Color(6), pixel(120, 120), "Should draw first cell in dendrogram order")
+	assert.Equal(t, scale.Color(5), pixel(132, 120), "Should draw second column in dendrogram order")
+	assert.Equal(t, scale.Color(1), pixel(137, 131), "Should draw second row in dendrogram order")
+	assert.Equal(t, scale.Color(math.NaN()), pixel(120, 144), "Should draw missing values")
+	assert.Equal(t, black, pixel(10, 135), "Should draw row dendrogram on the left")
+	assert.Equal(t, white, pixel(20, 135), "Should not draw outside dendrogram branches")
+	assert.Equal(t, black, pixel(126, 10), "Should draw column dendrogram on top")
+	assert.Equal(t, scale.Color(1+(5*0.5/60)), pixel(114, 158), "Should draw legend below heatmap")
+
+	// TEST2: cells smaller than a pixel and a diverging scale without dendrograms.
+	options := DefaultHeatmapOptions()
+	options.CellSize = 0.5
+	options.Legend = false
+	options.Scale = Diverging(0, 3, 6)
+	buffer.Reset()
+	HeatmapPNG(&buffer, testMatrix, rowNames, columnNames, nil, nil, options)
+	img, _ = png.Decode(&buffer)
+	assert.Equal(t, image.Rect(0, 0, 21, 22), img.Bounds(), "Should size image for small cells")
+	assert.Equal(t, options.Scale.Color(1), pixel(10, 10), "Should draw first cell")
+	assert.Equal(t, options.Scale.Color(6), pixel(11, 11), "Should draw later cells over earlier ones")
+
+	// TEST3: invalid input.
+	err = HeatmapPNG(&buffer, testMatrix, rowNames[:1], columnNames, nil, nil, DefaultHeatmapOptions())
+	assert.NotNil(t, err, "Should return an error for invalid input")
+}
+
+func TestRasterize(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	f := &figure{width: 20, height: 20}
+	f.line(2, 2, 18, 18, 1, red)
+	f.rect(0, 10, 5, 5, color.RGBA{0, 0, 128, 128})
+	img := rasterize(f)
+	assert.Equal(t, red, img.RGBAAt(10, 10), "Should draw diagonal line")
+	assert.Equal(t, color.RGBA{255, 255, 255, 255}, img.RGBAAt(10, 2), "Should not draw outside line")
+	assert.Equal(t, color.RGBA{127, 127, 255, 255}, img.RGBAAt(2, 12), "Should blend transparent colours")
+}
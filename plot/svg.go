@@ -0,0 +1,91 @@
+package plot
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// DendrogramSVG draws a dendrogram and its leaf names (which may be nil) as an
+// SVG image.
+func DendrogramSVG(w io.Writer, dendrogram typedef.Dendrogram, names []string, options DendrogramOptions) (err error) {
+	f, err := dendrogramFigure(dendrogram, names, options)
+	if err != nil {
+		return
+	}
+	return writeSVG(w, f)
+}
+
+// HeatmapSVG draws a data matrix as a clustered heatmap in SVG. Rows and
+// columns are ordered by their dendrograms, as for tree.Create and sort.Sort,
+// so names must be unique. Either dendrogram may be nil, in which case the
+// rows or columns keep their original order and no dendrogram is drawn.
+func HeatmapSVG(w io.Writer, matrix [][]float64, rowNames, columnNames []string, rowDendrogram, columnDendrogram typedef.Dendrogram, options HeatmapOptions) (err error) {
+	f, err := heatmapFigure(matrix, rowNames, columnNames, rowDendrogram, columnDendrogram, options)
+	if err != nil {
+		return
+	}
+	return writeSVG(w, f)
+}
+
+func writeSVG(w io.Writer, f *figure) error {
+	buffer := bufio.NewWriter(w)
+	width, height := formatNumber(f.width), formatNumber(f.height)
+	fmt.Fprintf(buffer, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" font-family="Arial, Helvetica, sans-serif">`+"\n", width, height, width, height)
+	buffer.WriteString(`<rect width="100%" height="100%" fill="#ffffff"/>` + "\n")
+	for _, shape := range f.shapes {
+		switch s := shape.(type) {
+		case line:
+			fmt.Fprintf(buffer, `<line x1="%s" y1="%s" x2="%s" y2="%s"%s stroke-width="%s" stroke-linecap="square"/>`+"\n",
+				formatNumber(s.x1), formatNumber(s.y1), formatNumber(s.x2), formatNumber(s.y2), svgPaint("stroke", s.color), formatNumber(s.width))
+		case arc:
+			// Arcs are drawn in two halves so an arc of a full circle has distinct
+			// start and end points.
+			point := func(angle float64) string {
+				return formatNumber(s.cx+(s.radius*math.Cos(angle))) + " " + formatNumber(s.cy-(s.radius*math.Sin(angle)))
+			}
+			radius := formatNumber(s.radius)
+			middle := (s.start + s.end) / 2
+			fmt.Fprintf(buffer, `<path d="M %s A %s %s 0 0 0 %s A %s %s 0 0 0 %s" fill="none"%s stroke-width="%s"/>`+"\n",
+				point(s.start), radius, radius, point(middle), radius, radius, point(s.end), svgPaint("stroke", s.color), formatNumber(s.width))
+		case rect:
+			fmt.Fprintf(buffer, `<rect x="%s" y="%s" width="%s" height="%s"%s/>`+"\n",
+				formatNumber(s.x), formatNumber(s.y), formatNumber(s.width), formatNumber(s.height), svgPaint("fill", s.color))
+		case grid:
+			for i, row := range s.values {
+				for j, value := range row {
+					fmt.Fprintf(buffer, `<rect x="%s" y="%s" width="%s" height="%s"%s/>`+"\n",
+						formatNumber(s.x+(float64(j)*s.size)), formatNumber(s.y+(float64(i)*s.size)), formatNumber(s.size), formatNumber(s.size), svgPaint("fill", s.scale.Color(value)))
+				}
+			}
+		case text:
+			fmt.Fprintf(buffer, `<text x="%s" y="%s" font-size="%s" text-anchor="%s" dominant-baseline="central"%s`,
+				formatNumber(s.x), formatNumber(s.y), formatNumber(s.size), s.anchor, svgPaint("fill", s.color))
+			if s.rotate != 0 {
+				fmt.Fprintf(buffer, ` transform="rotate(%s %s %s)"`, formatNumber(s.rotate), formatNumber(s.x), formatNumber(s.y))
+			}
+			buffer.WriteString(">")
+			xml.EscapeText(buffer, []byte(s.content))
+			buffer.WriteString("</text>\n")
+		}
+	}
+	buffer.WriteString("</svg>\n")
+	return buffer.Flush()
+}
+
+// svgPaint formats a colour as a fill or stroke attribute, with an opacity
+// attribute for transparent colours. Colours are alpha-premultiplied, as for
+// the image packages.
+func svgPaint(attribute string, c color.RGBA) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	paint := fmt.Sprintf(` %s="#%02x%02x%02x"`, attribute, n.R, n.G, n.B)
+	if n.A < 255 {
+		paint += fmt.Sprintf(` %s-opacity="%s"`, attribute, formatNumber(float64(n.A)/255))
+	}
+	return paint
+}
@@ -0,0 +1,93 @@
+package plot
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// svgElements counts the elements in an SVG document, failing if it is not
+// well-formed XML.
+func svgElements(t *testing.T, document string) map[string]int {
+	counts := make(map[string]int, 0)
+	decoder := xml.NewDecoder(strings.NewReader(document))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if !assert.Nil(t, err, "Should write well-formed XML") {
+			break
+		}
+		if element, ok := token.(xml.StartElement); ok {
+			counts[element.Name.Local]++
+		}
+	}
+	return counts
+}
+
+func TestWriteSVG(t *testing.T) {
+	f := &figure{width: 100, height: 50}
+	f.line(0, 0, 10.005, 20, 1, black)
+	f.arc(50, 50, 10, 0, math.Pi, 2, color.RGBA{128, 0, 0, 128})
+	f.rect(1, 2, 3, 4, color.RGBA{0, 0, 255, 255})
+	f.text(5, 6, 10, 90, "start", "a<b")
+
+	var buffer bytes.Buffer
+	err := writeSVG(&buffer, f)
+	want := `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="50" viewBox="0 0 100 50" font-family="Arial, Helvetica, sans-serif">` + "\n" +
+		`<rect width="100%" height="100%" fill="#ffffff"/>` + "\n" +
+		`<line x1="0" y1="0" x2="10.01" y2="20" stroke="#000000" stroke-width="1" stroke-linecap="square"/>` + "\n" +
+		`<path d="M 60 50 A 10 10 0 0 0 50 40 A 10 10 0 0 0 40 50" fill="none" stroke="#ff0000" stroke-opacity="0.5" stroke-width="2"/>` + "\n" +
+		`<rect x="1" y="2" width="3" height="4" fill="#0000ff"/>` + "\n" +
+		`<text x="5" y="6" font-size="10" text-anchor="start" dominant-baseline="central" fill="#000000" transform="rotate(90 5 6)">a&lt;b</text>` + "\n" +
+		"</svg>\n"
+	assert.Nil(t, err, "Should not return an error writing SVG")
+	assert.Equal(t, want, buffer.String(), "Should write shapes as SVG elements")
+}
+
+func TestDendrogramSVG(t *testing.T) {
+	// TEST1: write a dendrogram coloured by cluster.
+	var buffer bytes.Buffer
+	options := DefaultDendrogramOptions()
+	options.Clusters = []int{1, 1, 2, 2, 1}
+	err := DendrogramSVG(&buffer, testDendrogram, []string{"a", "b", "c", "d", "e"}, options)
+	counts := svgElements(t, buffer.String())
+	assert.Nil(t, err, "Should not return an error for a valid dendrogram")
+	assert.Equal(t, 12, counts["line"], "Should draw three lines for each elbow")
+	assert.Equal(t, 5, counts["text"], "Should draw a label for each leaf")
+	assert.Contains(t, buffer.String(), `stroke="#1f77b4"`, "Should colour branches by cluster")
+
+	// TEST2: write a radial dendrogram.
+	buffer.Reset()
+	options = DefaultDendrogramOptions()
+	options.Layout.Type = "radial"
+	DendrogramSVG(&buffer, testDendrogram, nil, options)
+	counts = svgElements(t, buffer.String())
+	assert.Equal(t, 3, counts["path"], "Should draw arcs for radial dendrogram")
+
+	// TEST3: invalid input.
+	err = DendrogramSVG(&buffer, testDendrogram, []string{"a"}, DefaultDendrogramOptions())
+	assert.NotNil(t, err, "Should return an error for invalid input")
+}
+
+func TestHeatmapSVG(t *testing.T) {
+	// TEST1: write a heatmap.
+	var buffer bytes.Buffer
+	err := HeatmapSVG(&buffer, testMatrix, []string{"r0", "r1", "r2"}, []string{"c0", "c1"}, testRowDendrogram, testColumnDendrogram, DefaultHeatmapOptions())
+	counts := svgElements(t, buffer.String())
+	assert.Nil(t, err, "Should not return an error for a valid heatmap")
+	assert.Equal(t, 1+6+60, counts["rect"], "Should draw background, cells and legend")
+	assert.Equal(t, 9, counts["line"], "Should draw row and column dendrograms")
+	assert.Equal(t, 3+2+2, counts["text"], "Should draw row, column and legend labels")
+
+	// TEST2: invalid input.
+	err = HeatmapSVG(&buffer, testMatrix, []string{"r0"}, []string{"c0", "c1"}, nil, nil, DefaultHeatmapOptions())
+	assert.NotNil(t, err, "Should return an error for invalid input")
+}
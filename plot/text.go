@@ -0,0 +1,304 @@
+package plot
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/knightjdr/hclust/layout"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// TextOptions control how a dendrogram is printed as text.
+type TextOptions struct {
+	// Width is the number of characters in each line, such as the width of the
+	// terminal. Names are shortened if there is not enough room for the tree.
+	Width int
+
+	// MaxLeafs is the maximum number of lines. Larger trees are truncated to the
+	// most levels of merges that fit, with collapsed nodes printed as the number
+	// of leafs under them in parentheses. A value of zero prints every leaf.
+	MaxLeafs int
+
+	// Clusters are optional cluster labels for each leaf, such as from cut.K,
+	// printed in brackets before each name. Collapsed nodes are marked when all
+	// of their leafs have the same label.
+	Clusters []int
+
+	// ASCII draws branches with -, | and + instead of box-drawing characters.
+	ASCII bool
+}
+
+// DefaultTextOptions returns the options for printing a tree of up to 50 leafs
+// in an 80 character terminal.
+func DefaultTextOptions() TextOptions {
+	return TextOptions{
+		Width:    80,
+		MaxLeafs: 50,
+	}
+}
+
+// Characters for drawing branches: horizontal, vertical, top corner, bottom
+// corner, and the joins where a parent branch meets a node at its top child,
+// bottom child or in between.
+var (
+	asciiGlyphs = [7]rune{'-', '|', '+', '+', '+', '+', '+'}
+	boxGlyphs   = [7]rune{'─', '│', '┌', '└', '┬', '┴', '┤'}
+)
+
+const (
+	minTreeWidth = 10
+	ellipsis     = "…"
+)
+
+// Text prints a dendrogram horizontally, with the top node on the left and one
+// leaf per line in the order of tree.Create. Branch lengths are scaled to fit
+// the width, though every node is at least one character to the right of its
+// parent. Names may be nil, in which case leafs are labelled by their number.
+func Text(w io.Writer, dendrogram typedef.Dendrogram, names []string, options TextOptions) (err error) {
+	n := dendrogram.NumLeafs()
+	if names != nil && len(names) != n {
+		return errors.New("The names vector must have the same dimension as the leaf number")
+	}
+	if err = validateClusters(options.Clusters, n); err != nil {
+		return
+	}
+	if options.MaxLeafs < 0 || options.MaxLeafs == 1 {
+		return errors.New("The maximum number of leafs must be 0 or at least 2")
+	}
+	l, err := layout.Create(dendrogram, layout.Options{Type: "rectangular", Levels: textLevels(dendrogram, options.MaxLeafs)})
+	if err != nil {
+		return
+	}
+
+	labels := leafLabels(l, names)
+	markers := clusterMarkers(dendrogram, l, options.Clusters)
+	for i, leaf := range l.Leafs {
+		if names == nil && !leaf.Collapsed {
+			labels[i] = strconv.Itoa(leaf.Node)
+		}
+	}
+
+	// Markers are padded to the same width and followed by a space.
+	markerWidth := 0
+	for _, marker := range markers {
+		markerWidth = intMax(markerWidth, utf8.RuneCountInString(marker))
+	}
+	if markerWidth > 0 {
+		markerWidth++
+	}
+	labelWidth := 0
+	for _, label := range labels {
+		labelWidth = intMax(labelWidth, utf8.RuneCountInString(label))
+	}
+
+	// Shorten names if the tree would be narrower than the minimum width.
+	treeWidth := options.Width - labelWidth - markerWidth - 1
+	if treeWidth < minTreeWidth {
+		treeWidth = minTreeWidth
+		labelWidth = intMax(1, options.Width-treeWidth-markerWidth-1)
+		for i, label := range labels {
+			if utf8.RuneCountInString(label) > labelWidth {
+				labels[i] = string([]rune(label)[:labelWidth-1]) + ellipsis
+			}
+		}
+	}
+
+	glyphs := boxGlyphs
+	if options.ASCII {
+		glyphs = asciiGlyphs
+	}
+	lines := drawTextTree(dendrogram, l, treeWidth, glyphs)
+
+	buffer := bufio.NewWriter(w)
+	for i, line := range lines {
+		buffer.WriteString(line + " ")
+		if markerWidth > 0 {
+			buffer.WriteString(markers[i] + strings.Repeat(" ", markerWidth-utf8.RuneCountInString(markers[i])))
+		}
+		buffer.WriteString(strings.TrimRight(labels[i], " ") + "\n")
+	}
+	return buffer.Flush()
+}
+
+// textLevels gets the most levels of merges that can be printed in maxLeafs
+// lines, or 0 if the whole tree fits.
+func textLevels(dendrogram typedef.Dendrogram, maxLeafs int) int {
+	n := dendrogram.NumLeafs()
+	if maxLeafs == 0 || n <= maxLeafs {
+		return 0
+	}
+
+	// Count the nodes at each depth and the leafs above each depth.
+	nodeIndex := make([]int, n-1)
+	for i, cluster := range dendrogram {
+		nodeIndex[cluster.Node-n] = i
+	}
+	nodesAtDepth := make([]int, 0)
+	leafsAtDepth := make([]int, 0)
+	type visit struct {
+		node, depth int
+	}
+	stack := []visit{{node: 2*n - 2}}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for len(nodesAtDepth) <= current.depth {
+			nodesAtDepth = append(nodesAtDepth, 0)
+			leafsAtDepth = append(leafsAtDepth, 0)
+		}
+		nodesAtDepth[current.depth]++
+		if current.node < n {
+			leafsAtDepth[current.depth]++
+			continue
+		}
+		cluster := dendrogram[nodeIndex[current.node-n]]
+		stack = append(stack, visit{cluster.Leafa, current.depth + 1}, visit{cluster.Leafb, current.depth + 1})
+	}
+
+	// Truncating at a level prints the nodes at that depth and the leafs above
+	// it.
+	levels := 1
+	leafsAbove := leafsAtDepth[0]
+	for depth := 2; depth < len(nodesAtDepth); depth++ {
+		leafsAbove += leafsAtDepth[depth-1]
+		if leafsAbove+nodesAtDepth[depth] > maxLeafs {
+			break
+		}
+		levels = depth
+	}
+	return levels
+}
+
+// clusterMarkers gets the cluster label of each leaf in a layout, or of the
+// leafs under a collapsed node if they all have the same label, in brackets.
+// Labels are found for every node in one pass from the leafs up.
+func clusterMarkers(dendrogram typedef.Dendrogram, l layout.Layout, clusters []int) []string {
+	markers := make([]string, len(l.Leafs))
+	if clusters == nil {
+		return markers
+	}
+
+	n := dendrogram.NumLeafs()
+	label := make([]int, 2*n-1)
+	uniform := make([]bool, 2*n-1)
+	copy(label, clusters)
+	for i := 0; i < n; i++ {
+		uniform[i] = true
+	}
+	for _, cluster := range dendrogram {
+		a, b := cluster.Leafa, cluster.Leafb
+		if uniform[a] && uniform[b] && label[a] == label[b] {
+			label[cluster.Node] = label[a]
+			uniform[cluster.Node] = true
+		}
+	}
+	for i, leaf := range l.Leafs {
+		if uniform[leaf.Node] {
+			markers[i] = "[" + strconv.Itoa(label[leaf.Node]) + "]"
+		}
+	}
+	return markers
+}
+
+// drawTextTree draws the branches of a layout, returning a line for each leaf.
+// Each node is on the line midway between its children (rounded down) and in the
+// column for its height.
+func drawTextTree(dendrogram typedef.Dendrogram, l layout.Layout, width int, glyphs [7]rune) []string {
+	n := dendrogram.NumLeafs()
+	nodeIndex := make([]int, n-1)
+	for i, cluster := range dendrogram {
+		nodeIndex[cluster.Node-n] = i
+	}
+	isLeaf := make(map[int]bool, len(l.Leafs))
+	row := make(map[int]int, len(l.Leafs)+len(l.Nodes))
+	for i, leaf := range l.Leafs {
+		isLeaf[leaf.Node] = true
+		row[leaf.Node] = i
+	}
+	children := func(node int) (int, int) {
+		cluster := dendrogram[nodeIndex[node-n]]
+		return cluster.Leafa, cluster.Leafb
+	}
+
+	// Nodes are in dendrogram order, so children are placed before their
+	// parents and parents before their children in reverse.
+	column := make(map[int]int, len(l.Nodes))
+	for _, node := range l.Nodes {
+		a, b := children(node.Node)
+		row[node.Node] = (row[a] + row[b]) / 2
+		column[node.Node] = 0
+		if l.Height > 0 {
+			column[node.Node] = int(math.Round((l.Height - node.Y) / l.Height * float64(width-2)))
+		}
+	}
+	leafColumn := width - 1
+	for i := len(l.Nodes) - 1; i >= 0; i-- {
+		node := l.Nodes[i].Node
+		if i == len(l.Nodes)-1 {
+			column[node] = 0
+		}
+		a, b := children(node)
+		for _, child := range []int{a, b} {
+			if !isLeaf[child] {
+				column[child] = intMax(column[child], column[node]+1)
+				leafColumn = intMax(leafColumn, column[child]+1)
+			}
+		}
+	}
+
+	grid := make([][]rune, len(l.Leafs))
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", leafColumn+1))
+	}
+	if len(l.Nodes) == 0 {
+		grid[0][leafColumn] = glyphs[0]
+	}
+	for _, position := range l.Nodes {
+		node := position.Node
+		x, y := column[node], row[node]
+		a, b := children(node)
+		top, bottom := row[a], row[b]
+		for r := top + 1; r < bottom; r++ {
+			grid[r][x] = glyphs[1]
+		}
+		grid[top][x], grid[bottom][x] = glyphs[2], glyphs[3]
+		if node != l.Nodes[len(l.Nodes)-1].Node {
+			if y == top {
+				grid[y][x] = glyphs[4]
+			} else if y == bottom {
+				grid[y][x] = glyphs[5]
+			} else {
+				grid[y][x] = glyphs[6]
+			}
+		}
+
+		// Branches to the children, ending at the leaf column for leafs.
+		for _, child := range []int{a, b} {
+			end := leafColumn
+			if !isLeaf[child] {
+				end = column[child] - 1
+			}
+			for c := x + 1; c <= end; c++ {
+				grid[row[child]][c] = glyphs[0]
+			}
+		}
+	}
+
+	lines := make([]string, len(grid))
+	for i, line := range grid {
+		lines[i] = string(line)
+	}
+	return lines
+}
+
+func intMax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
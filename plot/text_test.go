@@ -0,0 +1,93 @@
+package plot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/knightjdr/hclust/layout"
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestText(t *testing.T) {
+	names := []string{"alpha", "beta", "gamma", "delta", "epsilon"}
+
+	// TEST1: print with box-drawing characters.
+	var buffer bytes.Buffer
+	options := DefaultTextOptions()
+	options.Width = 30
+	err := Text(&buffer, testDendrogram, names, options)
+	want := "           ┌──────┬─── alpha\n" +
+		"┌──────────┤      └─── epsilon\n" +
+		"│          └────────── beta\n" +
+		"└─────┬─────────────── delta\n" +
+		"      └─────────────── gamma\n"
+	assert.Nil(t, err, "Should not return an error for a valid dendrogram")
+	assert.Equal(t, want, buffer.String(), "Should print tree scaled to width")
+
+	// TEST2: print in ASCII with cluster markers.
+	buffer.Reset()
+	options.ASCII = true
+	options.Clusters = []int{1, 1, 2, 2, 1}
+	Text(&buffer, testDendrogram, names, options)
+	want = "         +----+--- [1] alpha\n" +
+		"+--------+    +--- [1] epsilon\n" +
+		"|        +-------- [1] beta\n" +
+		"+----+------------ [2] delta\n" +
+		"     +------------ [2] gamma\n"
+	assert.Equal(t, want, buffer.String(), "Should print tree in ASCII with cluster markers")
+
+	// TEST3: truncate large trees and label leafs by number without names.
+	buffer.Reset()
+	options = DefaultTextOptions()
+	options.ASCII = true
+	options.MaxLeafs = 4
+	options.Width = 20
+	Text(&buffer, testDendrogram, nil, options)
+	want = "+-------+------- (2)\n" +
+		"|       +------- 1\n" +
+		"+---+----------- 3\n" +
+		"    +----------- 2\n"
+	assert.Equal(t, want, buffer.String(), "Should collapse nodes below the levels that fit")
+
+	// TEST4: shorten names when there is not enough room for the tree.
+	buffer.Reset()
+	Text(&buffer, testDendrogram, []string{"a", "bbbbbbbbbbbbbbb", "c", "d", "e"}, options)
+	assert.Contains(t, buffer.String(), "|   +----- bbbbbbbb…\n", "Should shorten long names")
+
+	// TEST5: single leaf.
+	buffer.Reset()
+	Text(&buffer, typedef.Dendrogram{}, []string{"only"}, options)
+	assert.Equal(t, "              - only\n", buffer.String(), "Should print a single leaf")
+
+	// TEST6: invalid input.
+	err = Text(&buffer, testDendrogram, names[:2], DefaultTextOptions())
+	assert.NotNil(t, err, "Should return an error when names do not match leafs")
+	options = DefaultTextOptions()
+	options.MaxLeafs = 1
+	err = Text(&buffer, testDendrogram, names, options)
+	assert.NotNil(t, err, "Should return an error when fewer than two leafs can be printed")
+}
+
+func TestTextLevels(t *testing.T) {
+	assert.Equal(t, 0, textLevels(testDendrogram, 0), "Should not truncate without a maximum")
+	assert.Equal(t, 0, textLevels(testDendrogram, 5), "Should not truncate a tree that fits")
+	assert.Equal(t, 2, textLevels(testDendrogram, 4), "Should truncate to the levels that fit")
+	assert.Equal(t, 1, textLevels(testDendrogram, 3), "Should truncate to one level")
+	assert.Equal(t, 1, textLevels(testDendrogram, 2), "Should truncate to the top node's children")
+}
+
+func TestClusterMarkers(t *testing.T) {
+	l, _ := layout.Create(testDendrogram, layout.Options{Type: "rectangular", Levels: 2})
+
+	// TEST1: collapsed nodes are marked when their leafs share a label.
+	want := []string{"[1]", "[1]", "[2]", "[2]"}
+	assert.Equal(t, want, clusterMarkers(testDendrogram, l, []int{1, 1, 2, 2, 1}), "Should mark leafs and uniform collapsed nodes")
+
+	// TEST2: collapsed nodes with mixed labels are not marked.
+	want = []string{"", "[1]", "[0]", "[2]"}
+	assert.Equal(t, want, clusterMarkers(testDendrogram, l, []int{1, 1, 2, 0, 0}), "Should not mark collapsed nodes with mixed labels")
+
+	// TEST3: no labels.
+	assert.Equal(t, []string{"", "", "", ""}, clusterMarkers(testDendrogram, l, nil), "Should not mark leafs without labels")
+}
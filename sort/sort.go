@@ -1,11 +1,7 @@
 // Package sort will sort a matrix by row or column.
 package sort
 
-import (
-	"errors"
-
-	"github.com/knightjdr/hclust/matrixop"
-)
+import "errors"
 
 // Sort takes a 2D matrix and sorts based on the columns or rows. A vector of
 // names must be supplied, along with the sorted order of the names. "dim"
@@ -25,11 +21,16 @@ func Sort(matrix [][]float64, names, sortOrder []string, dim string) (sorted [][
 		return
 	}
 
-	// Create sort map.
+	// Create sort map. Names are looked up by their first position in the sorted
+	// vector so large matrices can be sorted in linear time.
+	firstPos := make(map[string]int, len(sortOrder))
+	for j := len(sortOrder) - 1; j >= 0; j-- {
+		firstPos[sortOrder[j]] = j
+	}
 	sortedPos := make([]int, len(names))
 	for i, name := range names {
-		pos := matrixop.SliceIndex(len(sortOrder), func(j int) bool { return sortOrder[j] == name })
-		if pos < 0 {
+		pos, ok := firstPos[name]
+		if !ok {
 			err = errors.New("Name could not be found in sorted vector")
 			return
 		}
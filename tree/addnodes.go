@@ -6,6 +6,7 @@ type union struct {
 	Length    []float64
 	NextLabel int
 	Parent    []int
+	Size      []int
 }
 
 // Find highest incorporated parent node for a subnode n.
@@ -33,9 +34,10 @@ func (u *union) Find(n int, length float64) (int, float64) {
 	return n, nodeLength
 }
 
-// Set parent of most recently added node. Also set its length.
+// Set parent of most recently added node. Also set its length and size.
 func (u *union) AddParent(a, b int, length float64) {
 	u.Length[u.NextLabel] = length
+	u.Size[u.NextLabel] = u.Size[a] + u.Size[b]
 	u.Parent[a] = u.NextLabel
 	u.Parent[b] = u.NextLabel
 	u.NextLabel++
@@ -43,19 +45,21 @@ func (u *union) AddParent(a, b int, length float64) {
 
 // AddNodes adds numbered nodes to a dendrogram and converts distances between
 // leafs to branch lengths. The first new node will be equal to the length of
-// the dendrogram.
-func AddNodes(dendrogram []typedef.SubCluster) (labelledDendrogram []typedef.SubCluster) {
+// the dendrogram. The merge distance is kept as the node height.
+func AddNodes(dendrogram []typedef.SubCluster) (labelledDendrogram typedef.Dendrogram) {
 	// First parent node number.
 	n := len(dendrogram) + 1
 
 	// Create labels. Unknown parent nodes and node lengths are -1, i.e. not known.
 	length := make([]float64, 2*len(dendrogram)+1)
 	parent := make([]int, 2*len(dendrogram))
+	size := make([]int, 2*len(dendrogram)+1)
 	for i := range parent {
 		length[i] = -1
 		parent[i] = -1
+		size[i] = 1
 	}
-	labels := union{Length: length, NextLabel: n, Parent: parent}
+	labels := union{Length: length, NextLabel: n, Parent: parent, Size: size}
 
 	// First node to add.
 	for _, subcluster := range dendrogram {
@@ -69,6 +73,8 @@ func AddNodes(dendrogram []typedef.SubCluster) (labelledDendrogram []typedef.Sub
 				Lengtha: lengthA,
 				Lengthb: lengthB,
 				Node:    labels.NextLabel,
+				Height:  subcluster.Lengtha,
+				Size:    labels.Size[subnodeA] + labels.Size[subnodeB],
 			},
 		)
 		labels.AddParent(subnodeA, subnodeB, subcluster.Lengtha/float64(2))
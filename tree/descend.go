@@ -17,7 +17,8 @@ type Level struct {
 }
 
 // Descend descends down a tree and iterates over subnodes to find leaf order
-// and create newick strings.
+// and create newick strings. Descend recurses once per level of the tree; Write
+// produces the same newick string iteratively and is used by Create.
 func Descend(leafNum, node int, nodeMap map[int]int, dendrogram []typedef.SubCluster, names []string) (level Level) {
 	dendIndex := nodeMap[node]
 
@@ -26,7 +27,7 @@ func Descend(leafNum, node int, nodeMap map[int]int, dendrogram []typedef.SubClu
 		// Create new string for leaf and prepend to newick array.
 		leaf := names[dendrogram[dendIndex].Leafa]
 		length := strconv.FormatFloat(dendrogram[dendIndex].Lengtha, 'f', -1, 64)
-		leftString := fmt.Sprintf("(%s:%s,", leaf, length)
+		leftString := fmt.Sprintf("(%s:%s,", quoteName(leaf), length)
 		level.NewickArr = append([]string{leftString}, level.NewickArr...)
 
 		// Prepend new leaf to order.
@@ -55,7 +56,7 @@ func Descend(leafNum, node int, nodeMap map[int]int, dendrogram []typedef.SubClu
 		// Create new string for leaf and append to newick array.
 		leaf := names[dendrogram[dendIndex].Leafb]
 		length := strconv.FormatFloat(dendrogram[dendIndex].Lengthb, 'f', -1, 64)
-		rightString := fmt.Sprintf("%s:%s)", leaf, length)
+		rightString := fmt.Sprintf("%s:%s)", quoteName(leaf), length)
 		level.NewickArr = append(level.NewickArr, rightString)
 
 		// Append new leaf to order.
@@ -0,0 +1,326 @@
+package tree
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// parsedNode is a node read from a newick string before it is converted to a
+// dendrogram. The name is the leaf name or the internal node label.
+type parsedNode struct {
+	children []int
+	length   float64
+	name     string
+}
+
+// merge is a binary merge found while converting parsed nodes.
+type merge struct {
+	a, b             int
+	lengtha, lengthb float64
+	height           float64
+	label            string
+}
+
+// Parse reads a tree in newick format and returns the dendrogram, the leaf
+// names and the internal node labels in dendrogram order ("" for unlabelled
+// nodes). Leafs are numbered in the order they appear in the string and nodes
+// are numbered as for cluster.Cluster, in order of merge height. Quoted labels
+// (where a doubled single quote is a literal quote), comments in square
+// brackets and missing branch lengths (read as 0) are supported. The length of
+// the root branch is read but not kept. The tree must be binary unless
+// collapse is true, in which case nodes with one child are replaced by the
+// child (joining branch lengths and dropping the node's label) and nodes with
+// more than two children are resolved into binary nodes separated by branches
+// of length 0, with the label on the top node.
+//
+// Branch lengths are kept as written and the height of a node is twice the
+// length of its left branch plus the height of its left child, as for trees
+// written by Create. For trees that are not ultrametric, the right branch
+// lengths will not match the heights.
+func Parse(newick string, collapse bool) (dendrogram typedef.Dendrogram, names []string, labels []string, err error) {
+	nodes, root, err := parseNodes(newick)
+	if err != nil {
+		return
+	}
+
+	// Number leafs in order of appearance.
+	leafIndex := make(map[int]int, 0)
+	for i, node := range nodes {
+		if len(node.children) == 0 {
+			leafIndex[i] = len(names)
+			names = append(names, node.name)
+		}
+	}
+
+	merges, err := binaryMerges(nodes, root, leafIndex, collapse)
+	if err != nil {
+		return
+	}
+
+	// Order merges by height, keeping children before their parents, and number
+	// nodes from n.
+	nodeMerges := make([][2]int, len(merges))
+	heights := make([]float64, len(merges))
+	lengths := make([][2]float64, len(merges))
+	for i, m := range merges {
+		nodeMerges[i] = [2]int{m.a, m.b}
+		heights[i] = m.height
+		lengths[i] = [2]float64{m.lengtha, m.lengthb}
+	}
+	dendrogram, order, err := typedef.FromMerges(nodeMerges, heights, lengths, true)
+	if err != nil {
+		return
+	}
+	labels = make([]string, len(merges))
+	for i, index := range order {
+		labels[i] = merges[index].label
+	}
+	return
+}
+
+// binaryMerges converts parsed nodes to binary merges in post-order. Leafs are
+// numbered by leafIndex and merges from the number of leafs.
+func binaryMerges(nodes []parsedNode, root int, leafIndex map[int]int, collapse bool) (merges []merge, err error) {
+	n := len(leafIndex)
+	merges = make([]merge, 0, n-1)
+
+	// Dendrogram node and height for each parsed node, with the branch length to
+	// its parent (which may grow when a node with one child is collapsed).
+	id := make([]int, len(nodes))
+	height := make([]float64, len(nodes))
+	length := make([]float64, len(nodes))
+	for i, node := range nodes {
+		length[i] = node.length
+	}
+
+	type visit struct {
+		node     int
+		expanded bool
+	}
+	stack := []visit{{node: root}}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		children := nodes[top.node].children
+		if len(children) == 0 {
+			id[top.node] = leafIndex[top.node]
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if !top.expanded {
+			stack[len(stack)-1].expanded = true
+			for i := len(children) - 1; i >= 0; i-- {
+				stack = append(stack, visit{node: children[i]})
+			}
+			continue
+		}
+		stack = stack[:len(stack)-1]
+
+		if len(children) > 2 && !collapse {
+			err = fmt.Errorf("Tree is not binary: a node has %d children", len(children))
+			return
+		}
+		if len(children) == 1 {
+			if !collapse {
+				err = errors.New("Tree is not binary: a node has one child")
+				return
+			}
+			child := children[0]
+			id[top.node] = id[child]
+			height[top.node] = height[child]
+			length[top.node] += length[child]
+			continue
+		}
+
+		// Join the first two children, then join each remaining child to the
+		// previous merge with a branch of length 0.
+		first := children[0]
+		nodeHeight := (2 * length[first]) + height[first]
+		current, currentLength := id[first], length[first]
+		for _, child := range children[1:] {
+			merges = append(merges, merge{
+				a:       current,
+				b:       id[child],
+				lengtha: currentLength,
+				lengthb: length[child],
+				height:  nodeHeight,
+			})
+			current, currentLength = n+len(merges)-1, 0
+		}
+		merges[len(merges)-1].label = nodes[top.node].name
+		id[top.node] = current
+		height[top.node] = nodeHeight
+	}
+
+	if len(merges) != n-1 {
+		err = errors.New("Tree could not be converted to a dendrogram")
+	}
+	return
+}
+
+// newickDelimiters end unquoted labels and branch lengths.
+const newickDelimiters = "()[]':;,"
+
+// parseNodes reads the nodes of a newick string. Children are listed left to
+// right and leafs appear in the nodes slice in the order they appear in the
+// string.
+func parseNodes(newick string) (nodes []parsedNode, root int, err error) {
+	nodes = make([]parsedNode, 0)
+	root = -1
+	open := make([]int, 0)
+	last := -1
+	closed := false
+	done := false
+
+	addNode := func() (int, error) {
+		nodes = append(nodes, parsedNode{})
+		index := len(nodes) - 1
+		if len(open) > 0 {
+			parent := open[len(open)-1]
+			nodes[parent].children = append(nodes[parent].children, index)
+		} else if root >= 0 {
+			return index, errors.New("Newick string contains more than one tree")
+		} else {
+			root = index
+		}
+		return index, nil
+	}
+
+	i := 0
+	for i < len(newick) {
+		c := newick[i]
+		if done && !isSpace(c) && c != '[' {
+			err = errors.New("Unexpected characters after end of tree")
+			return
+		}
+
+		switch {
+		case isSpace(c):
+			i++
+		case c == '[':
+			end := strings.IndexByte(newick[i:], ']')
+			if end < 0 {
+				err = errors.New("Unterminated comment")
+				return
+			}
+			i += end + 1
+		case c == '(':
+			var index int
+			if index, err = addNode(); err != nil {
+				return
+			}
+			open = append(open, index)
+			last = -1
+			closed = false
+			i++
+		case c == ',' || c == ')':
+			if last < 0 {
+				err = errors.New("Leafs must be named")
+				return
+			}
+			if len(open) == 0 {
+				err = errors.New("Unbalanced parentheses in newick string")
+				return
+			}
+			if c == ')' {
+				last = open[len(open)-1]
+				open = open[:len(open)-1]
+				closed = true
+			} else {
+				last = -1
+				closed = false
+			}
+			i++
+		case c == ':':
+			if last < 0 {
+				err = errors.New("Branch length without a node")
+				return
+			}
+			i++
+			start := i
+			for i < len(newick) && !isSpace(newick[i]) && !strings.ContainsRune(newickDelimiters, rune(newick[i])) {
+				i++
+			}
+			if nodes[last].length, err = strconv.ParseFloat(newick[start:i], 64); err != nil {
+				err = fmt.Errorf("Invalid branch length: %s", newick[start:i])
+				return
+			}
+		case c == ';':
+			if len(open) > 0 {
+				err = errors.New("Unbalanced parentheses in newick string")
+				return
+			}
+			done = true
+			i++
+		default:
+			var label string
+			if label, i, err = readLabel(newick, i); err != nil {
+				return
+			}
+			if closed {
+				// Internal node label.
+				nodes[last].name = label
+				closed = false
+				continue
+			}
+			if last >= 0 {
+				err = errors.New("Unexpected label: " + label)
+				return
+			}
+			if last, err = addNode(); err != nil {
+				return
+			}
+			nodes[last].name = label
+		}
+	}
+
+	if len(open) > 0 {
+		err = errors.New("Unbalanced parentheses in newick string")
+		return
+	}
+	if root < 0 {
+		err = errors.New("Newick string does not contain a tree")
+		return
+	}
+	if len(nodes[root].children) == 0 && nodes[root].name == "" {
+		err = errors.New("Leafs must be named")
+	}
+	return
+}
+
+// readLabel reads a quoted or unquoted label starting at position i and
+// returns the label and the position after it. Underscores in unquoted labels
+// are read as spaces.
+func readLabel(newick string, i int) (label string, next int, err error) {
+	if newick[i] != '\'' {
+		start := i
+		for i < len(newick) && !isSpace(newick[i]) && !strings.ContainsRune(newickDelimiters, rune(newick[i])) {
+			i++
+		}
+		return strings.Replace(newick[start:i], "_", " ", -1), i, nil
+	}
+
+	var builder strings.Builder
+	i++
+	for i < len(newick) {
+		if newick[i] == '\'' {
+			if i+1 < len(newick) && newick[i+1] == '\'' {
+				builder.WriteByte('\'')
+				i += 2
+				continue
+			}
+			return builder.String(), i + 1, nil
+		}
+		builder.WriteByte(newick[i])
+		i++
+	}
+	err = errors.New("Unterminated quoted label")
+	return
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,113 @@
+package tree
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	// TEST1: parse a newick tree written by Create.
+	newick := "((leaf1:3.95,(leaf0:1,leaf4:1):2.95):4.71,(leaf2:6.1,leaf3:6.1):2.56)"
+	dendrogram, names, labels, err := Parse(newick, false)
+	want := typedef.Dendrogram{
+		{Leafa: 1, Leafb: 2, Lengtha: 1, Lengthb: 1, Node: 5, Height: 2, Size: 2},
+		{Leafa: 0, Leafb: 5, Lengtha: 3.95, Lengthb: 2.95, Node: 6, Height: 7.9, Size: 3},
+		{Leafa: 3, Leafb: 4, Lengtha: 6.1, Lengthb: 6.1, Node: 7, Height: 12.2, Size: 2},
+		{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.56, Node: 8, Height: 17.32, Size: 5},
+	}
+	assert.Nil(t, err, "Should not return an error for a valid tree")
+	assert.Equal(t, []string{"leaf1", "leaf0", "leaf4", "leaf2", "leaf3"}, names, "Should return names in order of appearance")
+	for i, cluster := range dendrogram {
+		assert.Equal(t, want[i].Leafa, cluster.Leafa, "Should parse left child")
+		assert.Equal(t, want[i].Leafb, cluster.Leafb, "Should parse right child")
+		assert.Equal(t, want[i].Lengtha, cluster.Lengtha, "Should parse left branch length")
+		assert.Equal(t, want[i].Lengthb, cluster.Lengthb, "Should parse right branch length")
+		assert.Equal(t, want[i].Node, cluster.Node, "Should number nodes")
+		assert.InDelta(t, want[i].Height, cluster.Height, 0.0001, "Should calculate merge height")
+		assert.Equal(t, want[i].Size, cluster.Size, "Should calculate node size")
+	}
+	assert.Equal(t, []string{"", "", "", ""}, labels, "Unlabelled nodes should have empty labels")
+	tree, _ := Create(dendrogram, names)
+	assert.Equal(t, newick, tree.Newick, "Parsed tree should create the same newick string")
+
+	// TEST2: nodes are numbered in order of merge height.
+	dendrogram, _, _, _ = Parse("((a:3,b:3):1,(c:1,d:1):3);", false)
+	assert.Equal(t, []int{2, 3}, []int{dendrogram[0].Leafa, dendrogram[0].Leafb}, "Lowest merge should be first")
+	assert.Equal(t, []int{5, 4}, []int{dendrogram[2].Leafa, dendrogram[2].Leafb}, "Should renumber nodes by height")
+
+	// TEST3: quoted labels, comments, missing lengths and internal labels.
+	dendrogram, names, labels, err = Parse("[tree 1] ('a b':1,('it''s':0.5,'c,d'[note]):0.5)inner:0.2 ; ", false)
+	assert.Nil(t, err, "Should not return an error for a valid tree")
+	assert.Equal(t, []string{"a b", "it's", "c,d"}, names, "Should parse quoted labels")
+	assert.Equal(t, float64(0), dendrogram[0].Lengthb, "Missing branch length should be 0")
+	assert.Equal(t, 4, dendrogram[1].Node, "Should parse tree with internal labels")
+	assert.Equal(t, []string{"", "inner"}, labels, "Should return internal node labels in dendrogram order")
+	_, names, _, _ = Parse("(leaf_1:1,'leaf_2':1);", false)
+	assert.Equal(t, []string{"leaf 1", "leaf_2"}, names, "Should read unquoted underscores as spaces")
+
+	// TEST4: non-binary trees.
+	_, _, _, err = Parse("(a:1,b:1,c:1);", false)
+	assert.NotNil(t, err, "Should return an error for a node with three children")
+	_, _, _, err = Parse("((a:1):1,b:2);", false)
+	assert.NotNil(t, err, "Should return an error for a node with one child")
+
+	// TEST5: collapse non-binary trees.
+	dendrogram, names, labels, err = Parse("(a:1,b:1,c:1)top;", true)
+	want = typedef.Dendrogram{
+		{Leafa: 0, Leafb: 1, Lengtha: 1, Lengthb: 1, Node: 3, Height: 2, Size: 2},
+		{Leafa: 3, Leafb: 2, Lengtha: 0, Lengthb: 1, Node: 4, Height: 2, Size: 3},
+	}
+	assert.Nil(t, err, "Should not return an error when collapsing")
+	assert.Equal(t, want, dendrogram, "Should resolve node with three children")
+	assert.Equal(t, []string{"", "top"}, labels, "Should label top node of resolved node")
+	dendrogram, _, _, _ = Parse("((a:1):1,b:2);", true)
+	want = typedef.Dendrogram{
+		{Leafa: 0, Leafb: 1, Lengtha: 2, Lengthb: 2, Node: 2, Height: 4, Size: 2},
+	}
+	assert.Equal(t, want, dendrogram, "Should collapse node with one child")
+
+	// TEST6: a single leaf.
+	dendrogram, names, labels, err = Parse("a;", false)
+	assert.Nil(t, err, "Should not return an error for a single leaf")
+	assert.Equal(t, typedef.Dendrogram{}, dendrogram, "Should return an empty dendrogram for a single leaf")
+	assert.Equal(t, []string{"a"}, names, "Should return name of single leaf")
+	assert.Equal(t, []string{}, labels, "Should return no internal node labels for a single leaf")
+
+	// TEST7: invalid trees.
+	invalid := []string{
+		"((a:1,b:1);",
+		"(a:1,b:1));",
+		"(a:1,:1);",
+		"(a:x,b:1);",
+		"('a:1,b:1);",
+		"(a:1,b:1); (c:1,d:1);",
+		"(a:1,b:1)[comment",
+		"",
+	}
+	for _, newick := range invalid {
+		_, _, _, err = Parse(newick, false)
+		assert.NotNil(t, err, "Should return an error for invalid tree: "+newick)
+	}
+
+	// TEST8: internal node labels and support values round trip.
+	options := DefaultOptions()
+	options.InternalLabel = "label"
+	options.NodeLabels = []string{"n1", "", "n 3", "95"}
+	labelled, labelledNames, _, _ := Parse(newick, false)
+	var buffer bytes.Buffer
+	WriteWithOptions(&buffer, labelled, labelledNames, options)
+	_, _, labels, err = Parse(buffer.String(), false)
+	assert.Nil(t, err, "Should not return an error for a labelled tree")
+	assert.Equal(t, options.NodeLabels, labels, "Internal node labels should round trip")
+
+	// TEST9: heights of trees that are not ultrametric come from the left branch.
+	dendrogram, _, _, _ = Parse("((a:1,b:2):1,c:3);", false)
+	want = typedef.Dendrogram{
+		{Leafa: 0, Leafb: 1, Lengtha: 1, Lengthb: 2, Node: 3, Height: 2, Size: 2},
+		{Leafa: 3, Leafb: 2, Lengtha: 1, Lengthb: 3, Node: 4, Height: 4, Size: 3},
+	}
+	assert.Equal(t, want, dendrogram, "Should keep branch lengths and take heights from the left branch")
+}
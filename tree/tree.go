@@ -1,7 +1,7 @@
 package tree
 
 import (
-	"errors"
+	"strings"
 
 	"github.com/knightjdr/hclust/typedef"
 )
@@ -14,26 +14,15 @@ type Tree struct {
 
 // Create generates a newick tree in string format and returns the order
 // of the clustering.
-func Create(dendrogram []typedef.SubCluster, names []string) (tree Tree, err error) {
-	// Return if names length does not match matix length.
-	if len(names) != len(dendrogram)+1 {
-		err = errors.New("The names vector must have the same dimension as the leaf number")
-		return
-	}
-
-	// Dendrogram clusters/leaf number.
-	n := len(dendrogram)
-
-	// Create map of nodes to dendrogram indicies.
-	nodeMap := make(map[int]int, n)
-	for i, cluster := range dendrogram {
-		nodeMap[cluster.Node] = i
-	}
+func Create(dendrogram typedef.Dendrogram, names []string) (tree Tree, err error) {
+	return CreateWithOptions(dendrogram, names, DefaultOptions())
+}
 
-	// Begin with top node, iterate through left and right branches and add to
-	// ordering.
-	level := Descend(n, 2*n, nodeMap, dendrogram, names)
-	tree.Newick = level.Newick
-	tree.Order = level.Order
+// CreateWithOptions generates a newick tree in string format, formatted with
+// options, and returns the order of the clustering.
+func CreateWithOptions(dendrogram typedef.Dendrogram, names []string, options Options) (tree Tree, err error) {
+	var builder strings.Builder
+	tree.Order, err = WriteWithOptions(&builder, dendrogram, names, options)
+	tree.Newick = builder.String()
 	return
 }
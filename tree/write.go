@@ -0,0 +1,172 @@
+package tree
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// Options control how a newick tree is written.
+type Options struct {
+	// Precision is the number of decimal places for branch lengths and numeric
+	// node labels. A negative value uses the fewest digits needed to represent
+	// each value exactly.
+	Precision int
+
+	// OmitLengths writes the tree without branch lengths.
+	OmitLengths bool
+
+	// InternalLabel is the label written for internal nodes: "id" for the node
+	// number, "label" for NodeLabels, "support" for Support or "height" for the
+	// merge height. Internal nodes are not labelled when it is empty.
+	InternalLabel string
+
+	// NodeLabels are the labels for internal nodes, in dendrogram order.
+	NodeLabels []string
+
+	// Support values for internal nodes (e.g. bootstrap support), in dendrogram
+	// order.
+	Support []float64
+}
+
+// DefaultOptions returns the options used by Create and Write.
+func DefaultOptions() Options {
+	return Options{
+		Precision: -1,
+	}
+}
+
+// newickReserved are the characters that require a name to be quoted. An
+// unquoted underscore is read as a space, so names with underscores are quoted
+// to keep them.
+const newickReserved = " \t\r\n()[]':;,_"
+
+// quoteName quotes a name if it contains characters with special meaning in
+// newick format, doubling any single quotes.
+func quoteName(name string) string {
+	if name != "" && !strings.ContainsAny(name, newickReserved) {
+		return name
+	}
+	return "'" + strings.Replace(name, "'", "''", -1) + "'"
+}
+
+// Write streams a dendrogram to w as a newick tree and returns the leaf order.
+// The tree is written iteratively in a single pass, so its depth is not limited
+// by the call stack and the newick string is not held in memory. Memory use is
+// O(depth) for the traversal, plus the returned leaf order.
+func Write(w io.Writer, dendrogram typedef.Dendrogram, names []string) (order []string, err error) {
+	return WriteWithOptions(w, dendrogram, names, DefaultOptions())
+}
+
+// WriteWithOptions streams a dendrogram to w as a newick tree formatted with
+// options and returns the leaf order.
+func WriteWithOptions(w io.Writer, dendrogram typedef.Dendrogram, names []string, options Options) (order []string, err error) {
+	n := len(dendrogram) + 1
+	if len(names) != n {
+		err = errors.New("The names vector must have the same dimension as the leaf number")
+		return
+	}
+	internalLabel, err := internalLabeler(dendrogram, options)
+	if err != nil {
+		return
+	}
+
+	// Map nodes to dendrogram indicies.
+	nodeIndex := make([]int, n-1)
+	for i, cluster := range dendrogram {
+		nodeIndex[cluster.Node-n] = i
+	}
+
+	buffer := bufio.NewWriter(w)
+	order = make([]string, 0, n)
+	writeLength := func(length float64) {
+		if !options.OmitLengths {
+			buffer.WriteString(":" + strconv.FormatFloat(length, 'f', options.Precision, 64))
+		}
+	}
+
+	// Each internal node is visited three times: before its left branch, between
+	// its branches and after its right branch.
+	type visit struct {
+		node  int
+		stage int
+	}
+	stack := []visit{{node: 2*n - 2}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.node < n {
+			buffer.WriteString(quoteName(names[top.node]))
+			order = append(order, names[top.node])
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		index := nodeIndex[top.node-n]
+		cluster := dendrogram[index]
+		switch top.stage {
+		case 0:
+			buffer.WriteByte('(')
+			top.stage++
+			stack = append(stack, visit{node: cluster.Leafa})
+		case 1:
+			writeLength(cluster.Lengtha)
+			buffer.WriteByte(',')
+			top.stage++
+			stack = append(stack, visit{node: cluster.Leafb})
+		default:
+			writeLength(cluster.Lengthb)
+			buffer.WriteByte(')')
+			buffer.WriteString(internalLabel(index))
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	err = buffer.Flush()
+	return
+}
+
+// internalLabeler returns a function for formatting the label of the internal
+// node at a dendrogram index.
+func internalLabeler(dendrogram typedef.Dendrogram, options Options) (labeler func(index int) string, err error) {
+	formatNumber := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', options.Precision, 64)
+	}
+
+	if options.InternalLabel == "" {
+		labeler = func(index int) string {
+			return ""
+		}
+	} else if options.InternalLabel == "id" {
+		labeler = func(index int) string {
+			return strconv.Itoa(dendrogram[index].Node)
+		}
+	} else if options.InternalLabel == "label" {
+		if len(options.NodeLabels) != len(dendrogram) {
+			err = errors.New("There must be one label for each internal node")
+			return
+		}
+		labeler = func(index int) string {
+			return quoteName(options.NodeLabels[index])
+		}
+	} else if options.InternalLabel == "support" {
+		if len(options.Support) != len(dendrogram) {
+			err = errors.New("There must be one support value for each internal node")
+			return
+		}
+		labeler = func(index int) string {
+			return formatNumber(options.Support[index])
+		}
+	} else if options.InternalLabel == "height" {
+		heights := dendrogram.Heights()
+		labeler = func(index int) string {
+			return formatNumber(heights[index])
+		}
+	} else {
+		err = errors.New("Unknown internal node label")
+	}
+	return
+}
@@ -0,0 +1,147 @@
+package tree
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestWrite(t *testing.T) {
+	// TEST1: write a tree.
+	dendrogram := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 3, Lengtha: 0.05, Lengthb: 0.05, Node: 6},
+		{Leafa: 2, Leafb: 5, Lengtha: 0.075, Lengthb: 0.075, Node: 7},
+		{Leafa: 1, Leafb: 6, Lengtha: 0.1, Lengthb: 0.05, Node: 8},
+		{Leafa: 4, Leafb: 8, Lengtha: 0.2, Lengthb: 0.1, Node: 9},
+		{Leafa: 7, Leafb: 9, Lengtha: 0.225, Lengthb: 0.1, Node: 10},
+	}
+	names := []string{"leaf0", "leaf1", "leaf2", "leaf3", "leaf4", "leaf5"}
+	var buffer bytes.Buffer
+	order, err := Write(&buffer, dendrogram, names)
+	assert.Nil(t, err, "Should not return an error for a valid tree")
+	assert.Equal(t, "((leaf2:0.075,leaf5:0.075):0.225,(leaf4:0.2,(leaf1:0.1,(leaf0:0.05,leaf3:0.05):0.05):0.1):0.1)", buffer.String(), "Should write newick tree")
+	assert.Equal(t, []string{"leaf2", "leaf5", "leaf4", "leaf1", "leaf0", "leaf3"}, order, "Should return leaf order")
+
+	// TEST2: a deep chained tree.
+	n := 100000
+	chain := make(typedef.Dendrogram, n-1)
+	names = make([]string, n)
+	names[0] = "leaf0"
+	for i := 1; i < n; i++ {
+		left := n + i - 2
+		if i == 1 {
+			left = 0
+		}
+		chain[i-1] = typedef.SubCluster{Leafa: left, Leafb: i, Lengtha: 0.5, Lengthb: 0.5, Node: n + i - 1}
+		names[i] = "leaf" + strconv.Itoa(i)
+	}
+	buffer.Reset()
+	order, err = Write(&buffer, chain, names)
+	assert.Nil(t, err, "Should not return an error for a deep tree")
+	assert.Equal(t, names, order, "Should return leaf order for a deep tree")
+	assert.True(t, strings.HasPrefix(buffer.String(), strings.Repeat("(", n-1)+"leaf0:0.5,leaf1:0.5)"), "Should write deep tree")
+
+	// TEST3: write errors are returned.
+	_, err = Write(failingWriter{}, dendrogram, []string{"leaf0", "leaf1", "leaf2", "leaf3", "leaf4", "leaf5"})
+	assert.NotNil(t, err, "Should return write errors")
+
+	// TEST4: names vector of incorrect length.
+	_, err = Write(&buffer, dendrogram, []string{"leaf0"})
+	assert.NotNil(t, err, "Incorrect length of names vector should return an error")
+}
+
+func TestQuoteName(t *testing.T) {
+	// TEST1: names that do not need quoting.
+	assert.Equal(t, "leaf1", quoteName("leaf1"), "Should not quote plain name")
+
+	// TEST2: names with reserved characters.
+	assert.Equal(t, "'sample 1'", quoteName("sample 1"), "Should quote name with a space")
+	assert.Equal(t, "'a:b,c'", quoteName("a:b,c"), "Should quote name with a colon and comma")
+	assert.Equal(t, "'leaf_1'", quoteName("leaf_1"), "Should quote name with an underscore")
+	assert.Equal(t, "'f(x)'", quoteName("f(x)"), "Should quote name with parentheses")
+	assert.Equal(t, "'it''s'", quoteName("it's"), "Should double single quotes")
+	assert.Equal(t, "''", quoteName(""), "Should quote empty name")
+}
+
+func TestWriteWithOptions(t *testing.T) {
+	dendrogram := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 1, Lengtha: 0.123456, Lengthb: 0.123456, Node: 3, Height: 0.246912},
+		{Leafa: 3, Leafb: 2, Lengtha: 0.376544, Lengthb: 0.5, Node: 4, Height: 1},
+	}
+	names := []string{"a b", "it's", "c"}
+	var buffer bytes.Buffer
+
+	// TEST1: default options quote names.
+	_, err := WriteWithOptions(&buffer, dendrogram, names, DefaultOptions())
+	assert.Nil(t, err, "Should not return an error with default options")
+	assert.Equal(t, "(('a b':0.123456,'it''s':0.123456):0.376544,c:0.5)", buffer.String(), "Should quote names")
+
+	// TEST2: precision.
+	options := DefaultOptions()
+	options.Precision = 2
+	buffer.Reset()
+	WriteWithOptions(&buffer, dendrogram, names, options)
+	assert.Equal(t, "(('a b':0.12,'it''s':0.12):0.38,c:0.50)", buffer.String(), "Should round branch lengths")
+
+	// TEST3: omit lengths with node ids.
+	options = DefaultOptions()
+	options.OmitLengths = true
+	options.InternalLabel = "id"
+	buffer.Reset()
+	WriteWithOptions(&buffer, dendrogram, names, options)
+	assert.Equal(t, "(('a b','it''s')3,c)4", buffer.String(), "Should omit lengths and write node ids")
+
+	// TEST4: node labels.
+	options = DefaultOptions()
+	options.OmitLengths = true
+	options.InternalLabel = "label"
+	options.NodeLabels = []string{"inner node", "root"}
+	buffer.Reset()
+	WriteWithOptions(&buffer, dendrogram, names, options)
+	assert.Equal(t, "(('a b','it''s')'inner node',c)root", buffer.String(), "Should write node labels")
+
+	// TEST5: support values and heights.
+	options = DefaultOptions()
+	options.Precision = 1
+	options.InternalLabel = "support"
+	options.Support = []float64{95, 100}
+	buffer.Reset()
+	WriteWithOptions(&buffer, dendrogram, names, options)
+	assert.Equal(t, "(('a b':0.1,'it''s':0.1)95.0:0.4,c:0.5)100.0", buffer.String(), "Should write support values")
+	options.InternalLabel = "height"
+	buffer.Reset()
+	WriteWithOptions(&buffer, dendrogram, names, options)
+	assert.Equal(t, "(('a b':0.1,'it''s':0.1)0.2:0.4,c:0.5)1.0", buffer.String(), "Should write heights")
+
+	// TEST6: invalid options.
+	options = DefaultOptions()
+	options.InternalLabel = "support"
+	_, err = WriteWithOptions(&buffer, dendrogram, names, options)
+	assert.NotNil(t, err, "Should return an error when support values are missing")
+	options.InternalLabel = "bootstrap"
+	_, err = WriteWithOptions(&buffer, dendrogram, names, options)
+	assert.NotNil(t, err, "Should return an error for an unknown internal label")
+
+	// TEST7: quoted names can be parsed.
+	tree, _ := Create(dendrogram, names)
+	_, parsedNames, _, err := Parse(tree.Newick, false)
+	assert.Nil(t, err, "Should parse tree with quoted names")
+	assert.Equal(t, names, parsedNames, "Should read quoted names")
+
+	// TEST8: names with underscores round trip.
+	names = []string{"leaf_1", "leaf 2", "leaf_3"}
+	tree, _ = Create(dendrogram, names)
+	_, parsedNames, _, _ = Parse(tree.Newick, false)
+	assert.Equal(t, names, parsedNames, "Should keep underscores in names")
+}
@@ -0,0 +1,142 @@
+package typedef
+
+// Dendrogram is an array of SubClusters, one for each internal node. Leafs are
+// numbered 0 to n-1 and the node at index i is numbered n+i, so every node is
+// listed after its children and the last node is the top of the tree.
+type Dendrogram []SubCluster
+
+// NumLeafs returns the number of leafs in the dendrogram.
+func (d Dendrogram) NumLeafs() int {
+	return len(d) + 1
+}
+
+// index returns the position of a node in the dendrogram or -1 if the node is a
+// leaf or does not exist.
+func (d Dendrogram) index(node int) int {
+	i := node - d.NumLeafs()
+	if i >= 0 && i < len(d) && d[i].Node == node {
+		return i
+	}
+	for j, cluster := range d {
+		if cluster.Node == node {
+			return j
+		}
+	}
+	return -1
+}
+
+// Heights returns the merge height of each node. Dendrograms created without
+// heights (every Height is zero) have their heights derived from the branch
+// lengths, which are half the difference in height between a node and its
+// children.
+func (d Dendrogram) Heights() []float64 {
+	heights := make([]float64, len(d))
+	hasHeights := false
+	for i, cluster := range d {
+		heights[i] = cluster.Height
+		if cluster.Height != 0 {
+			hasHeights = true
+		}
+	}
+	if hasHeights {
+		return heights
+	}
+
+	n := d.NumLeafs()
+	for i, cluster := range d {
+		childHeight := float64(0)
+		if cluster.Leafa >= n {
+			childHeight = heights[d.index(cluster.Leafa)]
+		}
+		heights[i] = (2 * cluster.Lengtha) + childHeight
+	}
+	return heights
+}
+
+// Height returns the merge height of a node. Leafs have a height of zero. Every
+// height is calculated on each call, so use Heights when looking up more than
+// one node.
+func (d Dendrogram) Height(node int) float64 {
+	i := d.index(node)
+	if i < 0 {
+		return 0
+	}
+	return d.Heights()[i]
+}
+
+// Children returns the two children of a node. Leafs and unknown nodes return
+// -1 for both children.
+func (d Dendrogram) Children(node int) (a, b int) {
+	i := d.index(node)
+	if i < 0 {
+		return -1, -1
+	}
+	return d[i].Leafa, d[i].Leafb
+}
+
+// Parent returns the parent of a node or leaf. The top node and unknown nodes
+// return -1. The dendrogram is searched on each call, so use a
+// dendrogram.Navigator when looking up parents for many nodes.
+func (d Dendrogram) Parent(node int) int {
+	for _, cluster := range d {
+		if cluster.Leafa == node || cluster.Leafb == node {
+			return cluster.Node
+		}
+	}
+	return -1
+}
+
+// Leafs returns the leafs under a node from left to right. A leaf returns
+// itself and an unknown node returns nil.
+func (d Dendrogram) Leafs(node int) []int {
+	n := d.NumLeafs()
+	if node >= 0 && node < n {
+		return []int{node}
+	}
+	if d.index(node) < 0 {
+		return nil
+	}
+
+	leafs := make([]int, 0)
+	stack := []int{node}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current < n {
+			leafs = append(leafs, current)
+			continue
+		}
+		cluster := d[d.index(current)]
+		stack = append(stack, cluster.Leafb, cluster.Leafa)
+	}
+	return leafs
+}
+
+// Sizes returns the number of leafs under each node. The Size of each node is
+// used when set, and dendrograms created without sizes (every Size is zero)
+// have their sizes counted from the merges.
+func (d Dendrogram) Sizes() []int {
+	sizes := make([]int, len(d))
+	hasSizes := false
+	for i, cluster := range d {
+		sizes[i] = cluster.Size
+		if cluster.Size != 0 {
+			hasSizes = true
+		}
+	}
+	if hasSizes {
+		return sizes
+	}
+
+	n := d.NumLeafs()
+	size := func(node int) int {
+		if node < n {
+			return 1
+		}
+		return sizes[d.index(node)]
+	}
+	for i, cluster := range d {
+		sizes[i] = size(cluster.Leafa) + size(cluster.Leafb)
+	}
+	return sizes
+}
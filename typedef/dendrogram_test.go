@@ -0,0 +1,60 @@
+package typedef
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDendrogram(t *testing.T) {
+	dendrogram := Dendrogram{
+		{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5, Height: 2, Size: 2},
+		{Leafa: 5, Leafb: 1, Lengtha: 2.95, Lengthb: 3.95, Node: 6, Height: 7.9, Size: 3},
+		{Leafa: 3, Leafb: 2, Lengtha: 6.1, Lengthb: 6.1, Node: 7, Height: 12.2, Size: 2},
+		{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.56, Node: 8, Height: 17.32, Size: 5},
+	}
+
+	// TEST1: number of leafs.
+	assert.Equal(t, 5, dendrogram.NumLeafs(), "Should return number of leafs")
+
+	// TEST2: heights.
+	assert.Equal(t, []float64{2, 7.9, 12.2, 17.32}, dendrogram.Heights(), "Should return merge heights")
+	assert.Equal(t, 12.2, dendrogram.Height(7), "Should return height of node")
+	assert.Equal(t, float64(0), dendrogram.Height(3), "Leaf should have a height of zero")
+
+	// TEST3: heights derived from branch lengths.
+	noHeights := Dendrogram{
+		{Leafa: 0, Leafb: 4, Lengtha: 1, Lengthb: 1, Node: 5},
+		{Leafa: 5, Leafb: 1, Lengtha: 2.95, Lengthb: 3.95, Node: 6},
+		{Leafa: 3, Leafb: 2, Lengtha: 6.1, Lengthb: 6.1, Node: 7},
+		{Leafa: 6, Leafb: 7, Lengtha: 4.71, Lengthb: 2.56, Node: 8},
+	}
+	wantHeights := []float64{2, 7.9, 12.2, 17.32}
+	for i, height := range noHeights.Heights() {
+		assert.InDelta(t, wantHeights[i], height, 0.0001, "Should derive heights from branch lengths")
+	}
+
+	// TEST4: sizes.
+	assert.Equal(t, []int{2, 3, 2, 5}, dendrogram.Sizes(), "Should return node sizes")
+	assert.Equal(t, []int{2, 3, 2, 5}, noHeights.Sizes(), "Should calculate node sizes")
+	storedSizes := append(Dendrogram{}, dendrogram...)
+	storedSizes[3].Size = 6
+	assert.Equal(t, []int{2, 3, 2, 6}, storedSizes.Sizes(), "Should return stored node sizes")
+
+	// TEST5: children.
+	a, b := dendrogram.Children(6)
+	assert.Equal(t, []int{5, 1}, []int{a, b}, "Should return children of node")
+	a, b = dendrogram.Children(2)
+	assert.Equal(t, []int{-1, -1}, []int{a, b}, "Leaf should not have children")
+
+	// TEST6: parent.
+	assert.Equal(t, 7, dendrogram.Parent(2), "Should return parent of leaf")
+	assert.Equal(t, 8, dendrogram.Parent(6), "Should return parent of node")
+	assert.Equal(t, -1, dendrogram.Parent(8), "Top node should not have a parent")
+
+	// TEST7: leafs under a node.
+	assert.Equal(t, []int{0, 4, 1}, dendrogram.Leafs(6), "Should return leafs under node")
+	assert.Equal(t, []int{0, 4, 1, 3, 2}, dendrogram.Leafs(8), "Should return all leafs under top node")
+	assert.Equal(t, []int{3}, dendrogram.Leafs(3), "Leaf should return itself")
+	assert.Nil(t, dendrogram.Leafs(9), "Unknown node should return nil")
+}
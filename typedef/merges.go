@@ -0,0 +1,89 @@
+package typedef
+
+import (
+	"errors"
+	"sort"
+)
+
+// FromMerges creates a dendrogram from merges of nodes numbered with the
+// dendrogram convention (leafs 0 to n-1 and the node created by merge i is
+// n+i) and the height of each merge. Branch lengths are taken from lengths
+// when it is not nil and are otherwise half the difference in height between a
+// node and its children. When sortByHeight is true merges are reordered by
+// height, keeping children before their parents and ties in their original
+// order, and nodes are renumbered from n. Order holds the index in merges of
+// each node in the dendrogram.
+func FromMerges(merges [][2]int, heights []float64, lengths [][2]float64, sortByHeight bool) (dendrogram Dendrogram, order []int, err error) {
+	n := len(merges) + 1
+	used := make([]bool, 2*n-1)
+	for i, merge := range merges {
+		node := n + i
+		for _, child := range merge {
+			if child < 0 || child >= node {
+				return nil, nil, errors.New("Merges must only refer to leafs and previously merged nodes")
+			}
+			if used[child] {
+				return nil, nil, errors.New("Each leaf and node can only be merged once")
+			}
+			used[child] = true
+		}
+		if merge[0] == merge[1] {
+			return nil, nil, errors.New("A node cannot be merged with itself")
+		}
+	}
+
+	order = make([]int, len(merges))
+	for i := range order {
+		order[i] = i
+	}
+	if sortByHeight {
+		// A node is placed no lower than its children so it stays after them.
+		monotone := make([]float64, len(merges))
+		for i, merge := range merges {
+			monotone[i] = heights[i]
+			for _, child := range merge {
+				if child >= n && monotone[child-n] > monotone[i] {
+					monotone[i] = monotone[child-n]
+				}
+			}
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return monotone[order[i]] < monotone[order[j]]
+		})
+	}
+	nodeNumber := make([]int, 2*n-1)
+	for i := 0; i < n; i++ {
+		nodeNumber[i] = i
+	}
+	for i, index := range order {
+		nodeNumber[n+index] = n + i
+	}
+
+	nodeHeight := make([]float64, 2*n-1)
+	size := make([]int, 2*n-1)
+	for i := 0; i < n; i++ {
+		size[i] = 1
+	}
+	dendrogram = make(Dendrogram, len(merges))
+	for i, index := range order {
+		a, b := nodeNumber[merges[index][0]], nodeNumber[merges[index][1]]
+		node := n + i
+		nodeHeight[node] = heights[index]
+		size[node] = size[a] + size[b]
+		dendrogram[i] = SubCluster{
+			Leafa:  a,
+			Leafb:  b,
+			Node:   node,
+			Height: heights[index],
+			Size:   size[node],
+		}
+		if lengths != nil {
+			dendrogram[i].Lengtha = lengths[index][0]
+			dendrogram[i].Lengthb = lengths[index][1]
+		} else {
+			dendrogram[i].Lengtha = (heights[index] - nodeHeight[a]) / 2
+			dendrogram[i].Lengthb = (heights[index] - nodeHeight[b]) / 2
+		}
+	}
+	return
+}
@@ -0,0 +1,46 @@
+package typedef
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromMerges(t *testing.T) {
+	merges := [][2]int{{0, 1}, {2, 3}, {4, 5}}
+
+	// TEST1: merges in order.
+	dendrogram, order, err := FromMerges(merges, []float64{1, 2, 4}, nil, false)
+	wantDendrogram := Dendrogram{
+		{Leafa: 0, Leafb: 1, Lengtha: 0.5, Lengthb: 0.5, Node: 4, Height: 1, Size: 2},
+		{Leafa: 2, Leafb: 3, Lengtha: 1, Lengthb: 1, Node: 5, Height: 2, Size: 2},
+		{Leafa: 4, Leafb: 5, Lengtha: 1.5, Lengthb: 1, Node: 6, Height: 4, Size: 4},
+	}
+	assert.Nil(t, err, "Should not return an error for valid merges")
+	assert.Equal(t, wantDendrogram, dendrogram, "Should create dendrogram from merges")
+	assert.Equal(t, []int{0, 1, 2}, order, "Should keep merges in order")
+
+	// TEST2: merges sorted by height, with branch lengths.
+	lengths := [][2]float64{{1.5, 1.5}, {1, 1}, {0.5, 1.5}}
+	dendrogram, order, err = FromMerges(merges, []float64{3, 2, 4}, lengths, true)
+	wantDendrogram = Dendrogram{
+		{Leafa: 2, Leafb: 3, Lengtha: 1, Lengthb: 1, Node: 4, Height: 2, Size: 2},
+		{Leafa: 0, Leafb: 1, Lengtha: 1.5, Lengthb: 1.5, Node: 5, Height: 3, Size: 2},
+		{Leafa: 5, Leafb: 4, Lengtha: 0.5, Lengthb: 1.5, Node: 6, Height: 4, Size: 4},
+	}
+	assert.Nil(t, err, "Should not return an error when sorting merges")
+	assert.Equal(t, wantDendrogram, dendrogram, "Should sort merges by height and renumber nodes")
+	assert.Equal(t, []int{1, 0, 2}, order, "Should return the original index of each merge")
+
+	// TEST3: a node below its children stays after them.
+	_, order, _ = FromMerges(merges, []float64{3, 2, 1}, nil, true)
+	assert.Equal(t, []int{1, 0, 2}, order, "Should keep children before their parents")
+
+	// TEST4: invalid merges.
+	_, _, err = FromMerges([][2]int{{0, 3}, {1, 2}}, []float64{1, 2}, nil, false)
+	assert.NotNil(t, err, "Should return an error for a merge with a later node")
+	_, _, err = FromMerges([][2]int{{0, 1}, {0, 2}}, []float64{1, 2}, nil, false)
+	assert.NotNil(t, err, "Should return an error when a leaf is merged twice")
+	_, _, err = FromMerges([][2]int{{0, 0}, {1, 2}}, []float64{1, 2}, nil, false)
+	assert.NotNil(t, err, "Should return an error when a leaf is merged with itself")
+}
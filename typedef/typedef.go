@@ -2,10 +2,18 @@
 package typedef
 
 // SubCluster stores the node, distance and names of leafs for a subcluster.
+// Height is the distance at which Leafa and Leafb were merged and Size is the
+// number of leafs under the node.
 type SubCluster struct {
 	Leafa   int
 	Leafb   int
 	Lengtha float64
 	Lengthb float64
 	Node    int
+	Height  float64
+	Size    int
 }
+
+// Progress reports the amount of work completed out of the total for long
+// running methods.
+type Progress func(completed, total int)
@@ -0,0 +1,51 @@
+package validity
+
+import (
+	"errors"
+)
+
+// CalinskiHarabasz calculates the Calinski-Harabasz index (variance ratio
+// criterion) from a data matrix with one row per leaf. It is the ratio of the
+// between-cluster to the within-cluster sum of squares, each divided by its
+// degrees of freedom. Higher values indicate better clusters. An error is
+// returned if every cluster has zero within-cluster sum of squares (for
+// example when all rows in each cluster are identical), as the index is
+// undefined.
+func CalinskiHarabasz(data [][]float64, labels []int) (index float64, err error) {
+	if err = validateData(data, labels); err != nil {
+		return
+	}
+	members := clusterMembers(labels)
+	k := len(members)
+	n := 0
+	for _, cluster := range members {
+		n += len(cluster)
+	}
+	if k < 2 || k >= n {
+		err = errors.New("The number of clusters must be at least two and less than the number of leafs")
+		return
+	}
+
+	centers := centroids(data, members)
+	overall := make([]float64, len(data[0]))
+	for i, cluster := range members {
+		for j := range overall {
+			overall[j] += centers[i][j] * float64(len(cluster)) / float64(n)
+		}
+	}
+
+	var between, within float64
+	for i, cluster := range members {
+		between += float64(len(cluster)) * squaredEuclidean(centers[i], overall)
+		for _, leaf := range cluster {
+			within += squaredEuclidean(data[leaf], centers[i])
+		}
+	}
+
+	if within == 0 {
+		err = errors.New("The within-cluster sum of squares must not be zero for the Calinski-Harabasz index")
+		return
+	}
+	index = (between / float64(k-1)) / (within / float64(n-k))
+	return
+}
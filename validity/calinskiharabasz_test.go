@@ -0,0 +1,22 @@
+package validity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalinskiHarabasz(t *testing.T) {
+	// TEST1: two clusters.
+	index, err := CalinskiHarabasz(testData, []int{1, 1, 2, 2})
+	assert.Nil(t, err, "Should not return an error for valid labels")
+	assert.InDelta(t, 32, index, 0.0001, "Should calculate Calinski-Harabasz index")
+
+	// TEST2: one cluster per leaf.
+	_, err = CalinskiHarabasz(testData, []int{1, 2, 3, 4})
+	assert.NotNil(t, err, "Should return an error when there is one cluster per leaf")
+
+	// TEST3: no within-cluster dispersion.
+	_, err = CalinskiHarabasz([][]float64{{0, 0}, {0, 0}, {1, 1}, {1, 1}}, []int{1, 1, 2, 2})
+	assert.NotNil(t, err, "Should return an error when the within-cluster sum of squares is zero")
+}
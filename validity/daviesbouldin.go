@@ -0,0 +1,50 @@
+package validity
+
+import (
+	"errors"
+	"math"
+)
+
+// DaviesBouldin calculates the Davies-Bouldin index from a data matrix with one
+// row per leaf. The scatter of a cluster is the average Euclidean distance of
+// its rows to the cluster centroid. The index is the mean, over clusters, of
+// the largest ratio of the summed scatter of two clusters to the distance
+// between their centroids. Lower values indicate better clusters. An error is
+// returned if two clusters have the same centroid, as the index is undefined.
+func DaviesBouldin(data [][]float64, labels []int) (index float64, err error) {
+	if err = validateData(data, labels); err != nil {
+		return
+	}
+	members := clusterMembers(labels)
+	if len(members) < 2 {
+		err = errors.New("At least two clusters are required for the Davies-Bouldin index")
+		return
+	}
+
+	centers := centroids(data, members)
+	scatter := make([]float64, len(members))
+	for i, cluster := range members {
+		for _, leaf := range cluster {
+			scatter[i] += math.Sqrt(squaredEuclidean(data[leaf], centers[i]))
+		}
+		scatter[i] /= float64(len(cluster))
+	}
+
+	for i := range members {
+		maxRatio := float64(0)
+		for j := range members {
+			if i == j {
+				continue
+			}
+			separation := math.Sqrt(squaredEuclidean(centers[i], centers[j]))
+			if separation == 0 {
+				return 0, errors.New("Clusters must have distinct centroids for the Davies-Bouldin index")
+			}
+			ratio := (scatter[i] + scatter[j]) / separation
+			maxRatio = math.Max(maxRatio, ratio)
+		}
+		index += maxRatio
+	}
+	index /= float64(len(members))
+	return
+}
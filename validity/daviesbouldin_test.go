@@ -0,0 +1,22 @@
+package validity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDaviesBouldin(t *testing.T) {
+	// TEST1: two clusters.
+	index, err := DaviesBouldin(testData, []int{1, 1, 2, 2})
+	assert.Nil(t, err, "Should not return an error for valid labels")
+	assert.InDelta(t, 0.25, index, 0.0001, "Should calculate Davies-Bouldin index")
+
+	// TEST2: a single cluster.
+	_, err = DaviesBouldin(testData, []int{1, 1, 1, 1})
+	assert.NotNil(t, err, "Should return an error for a single cluster")
+
+	// TEST3: clusters with the same centroid.
+	_, err = DaviesBouldin([][]float64{{0, 0}, {2, 2}, {0, 2}, {2, 0}}, []int{1, 1, 2, 2})
+	assert.NotNil(t, err, "Should return an error when clusters have the same centroid")
+}
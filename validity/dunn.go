@@ -0,0 +1,42 @@
+package validity
+
+import (
+	"errors"
+	"math"
+)
+
+// Dunn calculates the Dunn index, the smallest distance between leafs in
+// different clusters divided by the largest distance between leafs in the
+// same cluster. Higher values indicate compact, well-separated clusters.
+func Dunn(dist [][]float64, labels []int) (index float64, err error) {
+	if err = validateDistance(dist, labels); err != nil {
+		return
+	}
+	members := clusterMembers(labels)
+	if len(members) < 2 {
+		err = errors.New("At least two clusters are required for the Dunn index")
+		return
+	}
+
+	minSeparation := math.MaxFloat64
+	maxDiameter := float64(0)
+	for i, cluster := range members {
+		for _, a := range cluster {
+			for _, b := range cluster {
+				maxDiameter = math.Max(maxDiameter, dist[a][b])
+			}
+			for _, otherCluster := range members[i+1:] {
+				for _, b := range otherCluster {
+					minSeparation = math.Min(minSeparation, dist[a][b])
+				}
+			}
+		}
+	}
+
+	if maxDiameter == 0 {
+		index = math.Inf(1)
+		return
+	}
+	index = minSeparation / maxDiameter
+	return
+}
@@ -0,0 +1,18 @@
+package validity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDunn(t *testing.T) {
+	// TEST1: two clusters.
+	index, err := Dunn(testDist, []int{1, 1, 2, 2})
+	assert.Nil(t, err, "Should not return an error for valid labels")
+	assert.Equal(t, float64(3), index, "Should calculate Dunn index")
+
+	// TEST2: distance matrix does not match labels.
+	_, err = Dunn(testDist[:3], []int{1, 1, 2, 2})
+	assert.NotNil(t, err, "Should return an error when distance matrix does not match labels")
+}
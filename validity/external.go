@@ -0,0 +1,180 @@
+package validity
+
+import (
+	"errors"
+	"math"
+)
+
+// contingency counts the leafs shared by each cluster and each true class.
+// Leafs with a cluster label of 0 are unassigned and are ignored.
+type contingency struct {
+	classSizes   []float64
+	clusterSizes []float64
+	counts       [][]float64
+	n            float64
+}
+
+func newContingency(labels, truth []int) (table contingency, err error) {
+	if len(labels) != len(truth) {
+		err = errors.New("The cluster labels and true labels must have the same length")
+		return
+	}
+
+	clusterIndex := make(map[int]int, 0)
+	classIndex := make(map[int]int, 0)
+	for leaf, label := range labels {
+		if label == 0 {
+			continue
+		}
+		if _, ok := clusterIndex[label]; !ok {
+			clusterIndex[label] = len(clusterIndex)
+			table.clusterSizes = append(table.clusterSizes, 0)
+			table.counts = append(table.counts, make([]float64, 0))
+		}
+		if _, ok := classIndex[truth[leaf]]; !ok {
+			classIndex[truth[leaf]] = len(classIndex)
+			table.classSizes = append(table.classSizes, 0)
+		}
+	}
+	if len(clusterIndex) == 0 {
+		err = errors.New("At least one leaf must be assigned to a cluster")
+		return
+	}
+
+	for i := range table.counts {
+		table.counts[i] = make([]float64, len(classIndex))
+	}
+	for leaf, label := range labels {
+		if label == 0 {
+			continue
+		}
+		i := clusterIndex[label]
+		j := classIndex[truth[leaf]]
+		table.counts[i][j]++
+		table.clusterSizes[i]++
+		table.classSizes[j]++
+		table.n++
+	}
+	return
+}
+
+// pairs is the number of pairs that can be formed from n items.
+func pairs(n float64) float64 {
+	return n * (n - 1) / 2
+}
+
+// pairCounts gets the number of pairs of leafs in the same cluster and class,
+// in the same cluster and in the same class.
+func (table contingency) pairCounts() (both, sameCluster, sameClass float64) {
+	for i, row := range table.counts {
+		for _, count := range row {
+			both += pairs(count)
+		}
+		sameCluster += pairs(table.clusterSizes[i])
+	}
+	for _, size := range table.classSizes {
+		sameClass += pairs(size)
+	}
+	return
+}
+
+// AdjustedRand calculates the adjusted Rand index between cluster labels and
+// true labels. It is 1 for identical partitions and has an expected value of
+// 0 for random labels. At least two leafs must be assigned to clusters.
+func AdjustedRand(labels, truth []int) (index float64, err error) {
+	table, err := newContingency(labels, truth)
+	if err != nil {
+		return
+	}
+	if table.n < 2 {
+		err = errors.New("At least two leafs must be assigned to a cluster for the adjusted Rand index")
+		return
+	}
+
+	both, sameCluster, sameClass := table.pairCounts()
+	expected := sameCluster * sameClass / pairs(table.n)
+	maximum := (sameCluster + sameClass) / 2
+	if maximum == expected {
+		index = 1
+		return
+	}
+	index = (both - expected) / (maximum - expected)
+	return
+}
+
+// NormalizedMutualInformation calculates the mutual information between cluster
+// labels and true labels divided by the geometric mean of their entropies. It
+// ranges from 0 for independent partitions to 1 for identical partitions.
+func NormalizedMutualInformation(labels, truth []int) (nmi float64, err error) {
+	table, err := newContingency(labels, truth)
+	if err != nil {
+		return
+	}
+
+	entropy := func(sizes []float64) (h float64) {
+		for _, size := range sizes {
+			if size > 0 {
+				p := size / table.n
+				h -= p * math.Log(p)
+			}
+		}
+		return h
+	}
+	clusterEntropy := entropy(table.clusterSizes)
+	classEntropy := entropy(table.classSizes)
+	if clusterEntropy == 0 && classEntropy == 0 {
+		nmi = 1
+		return
+	}
+	if clusterEntropy == 0 || classEntropy == 0 {
+		return
+	}
+
+	mutualInformation := float64(0)
+	for i, row := range table.counts {
+		for j, count := range row {
+			if count > 0 {
+				mutualInformation += (count / table.n) * math.Log(count*table.n/(table.clusterSizes[i]*table.classSizes[j]))
+			}
+		}
+	}
+	nmi = mutualInformation / math.Sqrt(clusterEntropy*classEntropy)
+	return
+}
+
+// FowlkesMallows calculates the Fowlkes-Mallows index between cluster labels and
+// true labels, the geometric mean of the precision and recall of pairs of
+// leafs placed in the same cluster. It is 0 when no pairs of leafs share both a
+// cluster and a class.
+func FowlkesMallows(labels, truth []int) (index float64, err error) {
+	table, err := newContingency(labels, truth)
+	if err != nil {
+		return
+	}
+
+	both, sameCluster, sameClass := table.pairCounts()
+	if both == 0 {
+		return
+	}
+	index = both / math.Sqrt(sameCluster*sameClass)
+	return
+}
+
+// Purity calculates the fraction of leafs that belong to the most common true
+// class in their cluster.
+func Purity(labels, truth []int) (purity float64, err error) {
+	table, err := newContingency(labels, truth)
+	if err != nil {
+		return
+	}
+
+	for _, row := range table.counts {
+		largest := float64(0)
+		for _, count := range row {
+			largest = math.Max(largest, count)
+		}
+		purity += largest
+	}
+	purity /= table.n
+	return
+}
@@ -0,0 +1,65 @@
+package validity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdjustedRand(t *testing.T) {
+	// TEST1: partially matching partitions.
+	index, err := AdjustedRand([]int{1, 1, 1, 2, 2, 2}, []int{1, 1, 2, 2, 3, 3})
+	assert.Nil(t, err, "Should not return an error for valid labels")
+	assert.InDelta(t, 0.2424, index, 0.0001, "Should calculate adjusted Rand index")
+
+	// TEST2: identical partitions with different label values.
+	index, _ = AdjustedRand([]int{1, 1, 2, 2}, []int{5, 5, 3, 3})
+	assert.Equal(t, float64(1), index, "Should have an index of 1 for identical partitions")
+
+	// TEST3: labels of different lengths.
+	_, err = AdjustedRand([]int{1, 1, 2}, []int{1, 1})
+	assert.NotNil(t, err, "Should return an error for labels of different lengths")
+
+	// TEST4: fewer than two assigned leafs.
+	_, err = AdjustedRand([]int{1}, []int{1})
+	assert.NotNil(t, err, "Should return an error for a single leaf")
+	_, err = AdjustedRand([]int{0, 2, 0}, []int{1, 1, 2})
+	assert.NotNil(t, err, "Should return an error for a single assigned leaf")
+}
+
+func TestNormalizedMutualInformation(t *testing.T) {
+	// TEST1: partially matching partitions.
+	nmi, err := NormalizedMutualInformation([]int{1, 1, 1, 2, 2, 2}, []int{1, 1, 2, 2, 3, 3})
+	assert.Nil(t, err, "Should not return an error for valid labels")
+	assert.InDelta(t, 0.5296, nmi, 0.0001, "Should calculate normalized mutual information")
+
+	// TEST2: identical partitions.
+	nmi, _ = NormalizedMutualInformation([]int{1, 1, 2, 2}, []int{2, 2, 1, 1})
+	assert.InDelta(t, 1, nmi, 0.0001, "Should be 1 for identical partitions")
+
+	// TEST3: a single cluster.
+	nmi, _ = NormalizedMutualInformation([]int{1, 1, 1, 1}, []int{1, 1, 2, 2})
+	assert.Equal(t, float64(0), nmi, "Should be 0 when one partition has a single cluster")
+}
+
+func TestFowlkesMallows(t *testing.T) {
+	// TEST1: partially matching partitions.
+	index, err := FowlkesMallows([]int{1, 1, 1, 2, 2, 2}, []int{1, 1, 2, 2, 3, 3})
+	assert.Nil(t, err, "Should not return an error for valid labels")
+	assert.InDelta(t, 0.4714, index, 0.0001, "Should calculate Fowlkes-Mallows index")
+
+	// TEST2: unassigned leafs are ignored.
+	index, _ = FowlkesMallows([]int{1, 1, 0, 2, 2}, []int{1, 1, 1, 2, 2})
+	assert.InDelta(t, 1, index, 0.0001, "Should ignore unassigned leafs")
+
+	// TEST3: no leafs assigned.
+	_, err = FowlkesMallows([]int{0, 0}, []int{1, 1})
+	assert.NotNil(t, err, "Should return an error when no leafs are assigned")
+}
+
+func TestPurity(t *testing.T) {
+	// TEST1: partially matching partitions.
+	purity, err := Purity([]int{1, 1, 1, 2, 2, 2}, []int{1, 1, 2, 2, 3, 3})
+	assert.Nil(t, err, "Should not return an error for valid labels")
+	assert.InDelta(t, 0.6667, purity, 0.0001, "Should calculate purity")
+}
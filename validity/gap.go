@@ -0,0 +1,116 @@
+package validity
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/knightjdr/hclust/cluster"
+	"github.com/knightjdr/hclust/cut"
+	"github.com/knightjdr/hclust/distance"
+)
+
+// Gap calculates the gap statistic of Tibshirani et al. (J R Stat Soc B, 2001)
+// for each number of clusters in ks. The rows of the data matrix are clustered
+// using Euclidean distance and the linkage method, and the log of the pooled
+// within-cluster sum of squares for each cut is compared with its average over
+// reference datasets drawn uniformly from the range of each column. The seed
+// makes the reference datasets reproducible. The standard error of each gap
+// includes the simulation error, so a common choice of k is the smallest one
+// where gap[k] >= gap[k+1] - standardError[k+1].
+func Gap(data [][]float64, method string, ks []int, references int, seed int64) (gap, standardError []float64, err error) {
+	if len(data) < 2 {
+		err = errors.New("The data matrix must have at least two rows")
+		return
+	}
+	if err = validateData(data, make([]int, len(data))); err != nil {
+		return
+	}
+	if references < 1 {
+		err = errors.New("At least one reference dataset is required")
+		return
+	}
+
+	logW, err := logDispersion(data, method, ks)
+	if err != nil {
+		return
+	}
+
+	// Bounds of each column for drawing reference datasets.
+	columns := len(data[0])
+	lower := make([]float64, columns)
+	upper := make([]float64, columns)
+	for j := 0; j < columns; j++ {
+		lower[j] = math.MaxFloat64
+		upper[j] = -math.MaxFloat64
+		for _, row := range data {
+			lower[j] = math.Min(lower[j], row[j])
+			upper[j] = math.Max(upper[j], row[j])
+		}
+	}
+
+	random := rand.New(rand.NewSource(seed))
+	referenceLogW := make([][]float64, references)
+	for b := range referenceLogW {
+		reference := make([][]float64, len(data))
+		for i := range reference {
+			reference[i] = make([]float64, columns)
+			for j := range reference[i] {
+				reference[i][j] = lower[j] + (random.Float64() * (upper[j] - lower[j]))
+			}
+		}
+		if referenceLogW[b], err = logDispersion(reference, method, ks); err != nil {
+			return
+		}
+	}
+
+	gap = make([]float64, len(ks))
+	standardError = make([]float64, len(ks))
+	for i := range ks {
+		mean := float64(0)
+		for b := range referenceLogW {
+			mean += referenceLogW[b][i]
+		}
+		mean /= float64(references)
+
+		variance := float64(0)
+		for b := range referenceLogW {
+			variance += math.Pow(referenceLogW[b][i]-mean, 2)
+		}
+		variance /= float64(references)
+
+		gap[i] = mean - logW[i]
+		standardError[i] = math.Sqrt(variance) * math.Sqrt(1+(1/float64(references)))
+	}
+	return
+}
+
+// logDispersion clusters the rows of a data matrix and gets the log of the
+// pooled within-cluster sum of squares for each number of clusters in ks.
+func logDispersion(data [][]float64, method string, ks []int) (logW []float64, err error) {
+	dist := distance.Distance(data, "euclidean", false)
+	dendrogram, err := cluster.Cluster(dist, method)
+	if err != nil {
+		return
+	}
+	cuts, err := cut.Ks(dendrogram, ks)
+	if err != nil {
+		return
+	}
+
+	logW = make([]float64, len(ks))
+	for i, labels := range cuts {
+		w := float64(0)
+		for _, members := range clusterMembers(labels) {
+			sum := float64(0)
+			for a, leafa := range members {
+				for _, leafb := range members[a+1:] {
+					sum += dist[leafa][leafb] * dist[leafa][leafb]
+				}
+			}
+			w += sum / float64(len(members))
+		}
+		logW[i] = math.Log(w)
+	}
+	return
+}
@@ -0,0 +1,40 @@
+package validity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGap(t *testing.T) {
+	data := [][]float64{
+		{0, 0}, {0.2, 0.1}, {0.1, 0.3},
+		{5, 5}, {5.2, 5.1}, {4.9, 5.3},
+		{0, 5}, {0.3, 5.2}, {0.1, 4.8},
+	}
+	ks := []int{1, 2, 3, 4, 5}
+
+	// TEST1: the largest gap is at the true number of clusters.
+	gap, standardError, err := Gap(data, "average", ks, 20, 1)
+	assert.Nil(t, err, "Should not return an error for valid input")
+	assert.Len(t, gap, len(ks), "Should return a gap for each k")
+	assert.Len(t, standardError, len(ks), "Should return a standard error for each k")
+	for i := range gap {
+		if i != 2 {
+			assert.Greater(t, gap[2], gap[i], "Gap should be largest at three clusters")
+		}
+	}
+
+	// TEST2: the same seed gives the same result.
+	repeatGap, repeatStandardError, _ := Gap(data, "average", ks, 20, 1)
+	assert.Equal(t, gap, repeatGap, "Should return the same gaps for the same seed")
+	assert.Equal(t, standardError, repeatStandardError, "Should return the same standard errors for the same seed")
+
+	// TEST3: invalid input.
+	_, _, err = Gap(data, "average", []int{10}, 20, 1)
+	assert.NotNil(t, err, "Should return an error for an invalid k")
+	_, _, err = Gap(data, "average", ks, 0, 1)
+	assert.NotNil(t, err, "Should return an error when there are no reference datasets")
+	_, _, err = Gap(data, "unknown", ks, 20, 1)
+	assert.NotNil(t, err, "Should return an error for an unknown linkage method")
+}
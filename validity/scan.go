@@ -0,0 +1,52 @@
+package validity
+
+import (
+	"math"
+
+	"github.com/knightjdr/hclust/cut"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// Scores are the internal validity indices for one cut of a dendrogram.
+type Scores struct {
+	K                int
+	Silhouette       float64
+	Dunn             float64
+	DaviesBouldin    float64
+	CalinskiHarabasz float64
+}
+
+// Scan cuts a dendrogram into each number of clusters in ks and calculates the
+// internal validity indices for each cut. The silhouette width (mean) and Dunn
+// index are calculated from the distance matrix. The Davies-Bouldin and
+// Calinski-Harabasz indices are calculated from the data matrix and are NaN when
+// data is nil. Every k must be at least 2.
+func Scan(dendrogram typedef.Dendrogram, dist, data [][]float64, ks []int) (scores []Scores, err error) {
+	cuts, err := cut.Ks(dendrogram, ks)
+	if err != nil {
+		return
+	}
+
+	scores = make([]Scores, len(ks))
+	for i, labels := range cuts {
+		scores[i].K = ks[i]
+		if _, scores[i].Silhouette, err = Silhouette(dist, labels); err != nil {
+			return
+		}
+		if scores[i].Dunn, err = Dunn(dist, labels); err != nil {
+			return
+		}
+		if data == nil {
+			scores[i].DaviesBouldin = math.NaN()
+			scores[i].CalinskiHarabasz = math.NaN()
+			continue
+		}
+		if scores[i].DaviesBouldin, err = DaviesBouldin(data, labels); err != nil {
+			return
+		}
+		if scores[i].CalinskiHarabasz, err = CalinskiHarabasz(data, labels); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,36 @@
+package validity
+
+import (
+	"math"
+	"testing"
+
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScan(t *testing.T) {
+	dendrogram := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 1, Lengtha: 0.5, Lengthb: 0.5, Node: 4, Height: 1, Size: 2},
+		{Leafa: 2, Leafb: 3, Lengtha: 0.5, Lengthb: 0.5, Node: 5, Height: 1, Size: 2},
+		{Leafa: 4, Leafb: 5, Lengtha: 1.5, Lengthb: 1.5, Node: 6, Height: 4, Size: 4},
+	}
+
+	// TEST1: scores with a data matrix.
+	scores, err := Scan(dendrogram, testDist, testData, []int{2, 3})
+	assert.Nil(t, err, "Should not return an error for valid input")
+	assert.Equal(t, 2, scores[0].K, "Should report k")
+	assert.InDelta(t, 0.7460, scores[0].Silhouette, 0.0001, "Should calculate silhouette width for k")
+	assert.Equal(t, float64(3), scores[0].Dunn, "Should calculate Dunn index for k")
+	assert.InDelta(t, 0.25, scores[0].DaviesBouldin, 0.0001, "Should calculate Davies-Bouldin index for k")
+	assert.InDelta(t, 32, scores[0].CalinskiHarabasz, 0.0001, "Should calculate Calinski-Harabasz index for k")
+	assert.Equal(t, 3, scores[1].K, "Should report each k")
+
+	// TEST2: data indices are NaN without a data matrix.
+	scores, _ = Scan(dendrogram, testDist, nil, []int{2})
+	assert.True(t, math.IsNaN(scores[0].DaviesBouldin), "Davies-Bouldin index should be NaN without data")
+	assert.True(t, math.IsNaN(scores[0].CalinskiHarabasz), "Calinski-Harabasz index should be NaN without data")
+
+	// TEST3: a single cluster.
+	_, err = Scan(dendrogram, testDist, testData, []int{1})
+	assert.NotNil(t, err, "Should return an error for a single cluster")
+}
@@ -0,0 +1,58 @@
+package validity
+
+import (
+	"errors"
+	"math"
+)
+
+// Silhouette calculates the silhouette width of each leaf and the mean width.
+// The width for a leaf is (b - a) / max(a, b), where a is the average distance
+// to the other leafs in its cluster and b is the smallest average distance to
+// the leafs of another cluster. Leafs in a cluster by themselves have a width
+// of 0. Unassigned leafs (label 0) have a width of 0 and are not included in
+// the mean.
+func Silhouette(dist [][]float64, labels []int) (widths []float64, mean float64, err error) {
+	if err = validateDistance(dist, labels); err != nil {
+		return
+	}
+	members := clusterMembers(labels)
+	if len(members) < 2 {
+		err = errors.New("At least two clusters are required for the silhouette width")
+		return
+	}
+
+	widths = make([]float64, len(labels))
+	assigned := 0
+	for own, cluster := range members {
+		for _, leaf := range cluster {
+			assigned++
+			if len(cluster) == 1 {
+				continue
+			}
+
+			a := averageDistance(dist, leaf, cluster) * float64(len(cluster)) / float64(len(cluster)-1)
+			b := math.MaxFloat64
+			for other, otherCluster := range members {
+				if other != own {
+					b = math.Min(b, averageDistance(dist, leaf, otherCluster))
+				}
+			}
+			if larger := math.Max(a, b); larger > 0 {
+				widths[leaf] = (b - a) / larger
+			}
+			mean += widths[leaf]
+		}
+	}
+	mean /= float64(assigned)
+	return
+}
+
+// averageDistance gets the average distance from a leaf to the leafs in a
+// cluster.
+func averageDistance(dist [][]float64, leaf int, cluster []int) float64 {
+	sum := float64(0)
+	for _, member := range cluster {
+		sum += dist[leaf][member]
+	}
+	return sum / float64(len(cluster))
+}
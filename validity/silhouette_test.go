@@ -0,0 +1,37 @@
+package validity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testData = [][]float64{{0}, {1}, {4}, {5}}
+
+var testDist = [][]float64{
+	{0, 1, 4, 5},
+	{1, 0, 3, 4},
+	{4, 3, 0, 1},
+	{5, 4, 1, 0},
+}
+
+func TestSilhouette(t *testing.T) {
+	// TEST1: two clusters.
+	widths, mean, err := Silhouette(testDist, []int{1, 1, 2, 2})
+	wantWidths := []float64{0.7778, 0.7143, 0.7143, 0.7778}
+	assert.Nil(t, err, "Should not return an error for valid labels")
+	for i, width := range widths {
+		assert.InDelta(t, wantWidths[i], width, 0.0001, "Should calculate silhouette width for each leaf")
+	}
+	assert.InDelta(t, 0.7460, mean, 0.0001, "Should calculate mean silhouette width")
+
+	// TEST2: leafs in a cluster by themselves and unassigned leafs have a width of zero.
+	widths, mean, _ = Silhouette(testDist, []int{1, 1, 2, 0})
+	assert.Equal(t, float64(0), widths[2], "Leaf in a cluster by itself should have a width of zero")
+	assert.Equal(t, float64(0), widths[3], "Unassigned leaf should have a width of zero")
+	assert.InDelta(t, (0.75+(2.0/3.0))/3, mean, 0.0001, "Should not include unassigned leafs in mean")
+
+	// TEST3: a single cluster.
+	_, _, err = Silhouette(testDist, []int{1, 1, 1, 1})
+	assert.NotNil(t, err, "Should return an error for a single cluster")
+}
@@ -0,0 +1,71 @@
+package validity
+
+import (
+	"errors"
+	"math"
+
+	"github.com/knightjdr/hclust/cut"
+	"github.com/knightjdr/hclust/typedef"
+)
+
+// FowlkesMallowsSweep cuts a dendrogram into every number of clusters from 2 to
+// n-1 and calculates the Fowlkes-Mallows index (Bk) of each cut against the
+// true labels. The index is updated as each merge is made rather than cutting
+// the dendrogram at every k, so the sweep takes O(n) memory.
+func FowlkesMallowsSweep(dendrogram typedef.Dendrogram, truth []int) (ks []int, bk []float64, err error) {
+	n := dendrogram.NumLeafs()
+	if len(truth) != n {
+		err = errors.New("There must be one true label for each leaf")
+		return
+	}
+	if n < 3 {
+		err = errors.New("At least three leafs are required for a Fowlkes-Mallows sweep")
+		return
+	}
+
+	// Merges are made in the order used by cut.Ks, starting from one cluster per
+	// leaf. Joining clusters a and b adds |a|·|b| pairs of leafs in the same
+	// cluster and the sum over classes of a_c·b_c pairs in the same cluster and
+	// class, so Bk is updated as each merge is made. The class counts of the
+	// smaller cluster are added to those of the larger one.
+	classCounts := make([]map[int]int, 2*n-1)
+	size := make([]int, 2*n-1)
+	classSizes := make(map[int]int, 0)
+	for leaf, class := range truth {
+		classCounts[leaf] = map[int]int{class: 1}
+		size[leaf] = 1
+		classSizes[class]++
+	}
+	sameClass := float64(0)
+	for _, classSize := range classSizes {
+		sameClass += pairs(float64(classSize))
+	}
+
+	ks = make([]int, n-2)
+	bk = make([]float64, n-2)
+	var both, sameCluster float64
+	for m, index := range cut.MergeOrder(dendrogram) {
+		cluster := dendrogram[index]
+		a, b := cluster.Leafa, cluster.Leafb
+		if size[a] < size[b] {
+			a, b = b, a
+		}
+		sameCluster += float64(size[a] * size[b])
+		for class, count := range classCounts[b] {
+			both += float64(classCounts[a][class] * count)
+			classCounts[a][class] += count
+		}
+		classCounts[cluster.Node], classCounts[a], classCounts[b] = classCounts[a], nil, nil
+		size[cluster.Node] = size[a] + size[b]
+
+		k := n - m - 1
+		if k < 2 {
+			break
+		}
+		ks[k-2] = k
+		if both > 0 {
+			bk[k-2] = both / math.Sqrt(sameCluster*sameClass)
+		}
+	}
+	return
+}
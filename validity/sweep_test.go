@@ -0,0 +1,47 @@
+package validity
+
+import (
+	"testing"
+
+	"github.com/knightjdr/hclust/cut"
+	"github.com/knightjdr/hclust/typedef"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFowlkesMallowsSweep(t *testing.T) {
+	dendrogram := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 1, Lengtha: 0.5, Lengthb: 0.5, Node: 4, Height: 1, Size: 2},
+		{Leafa: 2, Leafb: 3, Lengtha: 0.5, Lengthb: 0.5, Node: 5, Height: 1, Size: 2},
+		{Leafa: 4, Leafb: 5, Lengtha: 1.5, Lengthb: 1.5, Node: 6, Height: 4, Size: 4},
+	}
+
+	// TEST1: index for each k.
+	ks, bk, err := FowlkesMallowsSweep(dendrogram, []int{1, 1, 2, 2})
+	assert.Nil(t, err, "Should not return an error for valid labels")
+	assert.Equal(t, []int{2, 3}, ks, "Should return each k from 2 to n-1")
+	assert.InDelta(t, 1, bk[0], 0.0001, "Should calculate Bk for two clusters")
+	assert.InDelta(t, 0.7071, bk[1], 0.0001, "Should calculate Bk for three clusters")
+
+	// TEST2: matches cutting the dendrogram at each k, including tied heights.
+	larger := typedef.Dendrogram{
+		{Leafa: 0, Leafb: 5, Node: 8, Height: 1, Size: 2},
+		{Leafa: 2, Leafb: 3, Node: 9, Height: 1, Size: 2},
+		{Leafa: 8, Leafb: 1, Node: 10, Height: 2, Size: 3},
+		{Leafa: 4, Leafb: 7, Node: 11, Height: 2, Size: 2},
+		{Leafa: 9, Leafb: 6, Node: 12, Height: 3, Size: 3},
+		{Leafa: 10, Leafb: 11, Node: 13, Height: 5, Size: 5},
+		{Leafa: 13, Leafb: 12, Node: 14, Height: 6, Size: 8},
+	}
+	truth := []int{1, 2, 1, 3, 2, 1, 3, 2}
+	ks, bk, err = FowlkesMallowsSweep(larger, truth)
+	assert.Nil(t, err, "Should not return an error for a larger dendrogram")
+	cuts, _ := cut.Ks(larger, ks)
+	for i, labels := range cuts {
+		want, _ := FowlkesMallows(labels, truth)
+		assert.InDelta(t, want, bk[i], 0.0001, "Should match the index of each cut")
+	}
+
+	// TEST3: wrong number of true labels.
+	_, _, err = FowlkesMallowsSweep(dendrogram, []int{1, 1, 2})
+	assert.NotNil(t, err, "Should return an error when there is not one label per leaf")
+}
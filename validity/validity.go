@@ -0,0 +1,84 @@
+// Package validity has indices for assessing the quality of flat clusters.
+package validity
+
+import (
+	"errors"
+	"sort"
+)
+
+// clusterMembers groups leafs by cluster label. Leafs with a label of 0 are
+// unassigned and are ignored. Clusters are returned in order of their label.
+func clusterMembers(labels []int) (members [][]int) {
+	byLabel := make(map[int][]int, 0)
+	for leaf, label := range labels {
+		if label != 0 {
+			byLabel[label] = append(byLabel[label], leaf)
+		}
+	}
+
+	clusterLabels := make([]int, 0, len(byLabel))
+	for label := range byLabel {
+		clusterLabels = append(clusterLabels, label)
+	}
+	sort.Ints(clusterLabels)
+
+	members = make([][]int, len(clusterLabels))
+	for i, label := range clusterLabels {
+		members[i] = byLabel[label]
+	}
+	return members
+}
+
+// validateDistance checks that a distance matrix is square with one row per
+// label.
+func validateDistance(dist [][]float64, labels []int) (err error) {
+	if len(dist) != len(labels) {
+		return errors.New("The distance matrix must have one row for each label")
+	}
+	for _, row := range dist {
+		if len(row) != len(labels) {
+			return errors.New("The distance matrix must be square")
+		}
+	}
+	return
+}
+
+// validateData checks that a data matrix has one row per label and that every
+// row has the same length.
+func validateData(data [][]float64, labels []int) (err error) {
+	if len(data) != len(labels) {
+		return errors.New("The data matrix must have one row for each label")
+	}
+	for _, row := range data {
+		if len(row) != len(data[0]) {
+			return errors.New("All rows in the data matrix must have the same length")
+		}
+	}
+	return
+}
+
+// centroids gets the mean of the rows in each cluster.
+func centroids(data [][]float64, members [][]int) [][]float64 {
+	centers := make([][]float64, len(members))
+	for i, cluster := range members {
+		centers[i] = make([]float64, len(data[0]))
+		for _, leaf := range cluster {
+			for j, value := range data[leaf] {
+				centers[i][j] += value
+			}
+		}
+		for j := range centers[i] {
+			centers[i][j] /= float64(len(cluster))
+		}
+	}
+	return centers
+}
+
+// squaredEuclidean gets the squared Euclidean distance between two vectors.
+func squaredEuclidean(x, y []float64) (sum float64) {
+	for i := range x {
+		diff := x[i] - y[i]
+		sum += diff * diff
+	}
+	return sum
+}
@@ -0,0 +1,33 @@
+package validity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterMembers(t *testing.T) {
+	// TEST1: group leafs by label, ignoring unassigned leafs.
+	labels := []int{2, 1, 0, 2, 3, 1}
+	want := [][]int{
+		{1, 5},
+		{0, 3},
+		{4},
+	}
+	assert.Equal(t, want, clusterMembers(labels), "Should group leafs by cluster label")
+}
+
+func TestCentroids(t *testing.T) {
+	// TEST1: mean of rows in each cluster.
+	data := [][]float64{
+		{0, 1},
+		{2, 3},
+		{4, 4},
+	}
+	members := [][]int{{0, 1}, {2}}
+	want := [][]float64{
+		{1, 2},
+		{4, 4},
+	}
+	assert.Equal(t, want, centroids(data, members), "Should calculate cluster centroids")
+}